@@ -2,13 +2,28 @@ package debug
 
 import (
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/certusone/wormhole/bridge/pkg/common"
 	"github.com/certusone/wormhole/bridge/pkg/vaa"
 	"github.com/davecgh/go-spew/spew"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"log"
 	"strings"
 )
 
+var (
+	decodeVaaJSON      *bool
+	decodeVaaGuardians *[]string
+)
+
+func init() {
+	decodeVaaJSON = decodeVaaCmd.Flags().Bool("json", false, "Print the decoded VAA as JSON")
+	decodeVaaGuardians = decodeVaaCmd.Flags().StringSlice("guardians", nil,
+		"Verify signatures against the guardian set with these addresses (in order) and the VAA's guardian set index")
+}
+
 var decodeVaaCmd = &cobra.Command{
 	Use:   "decode-vaa [DATA]",
 	Short: "Decode a hex-encoded VAA",
@@ -25,7 +40,64 @@ var decodeVaaCmd = &cobra.Command{
 				log.Fatal(err)
 			}
 
-			spew.Dump(v)
+			if *decodeVaaJSON {
+				j, err := json.MarshalIndent(v, "", "  ")
+				if err != nil {
+					log.Fatal(err)
+				}
+				fmt.Println(string(j))
+			} else {
+				spew.Dump(v)
+				printAddresses(v)
+			}
+
+			if len(*decodeVaaGuardians) > 0 {
+				printSignatureReport(v, *decodeVaaGuardians)
+			}
 		}
 	},
 }
+
+// printAddresses prints the addresses in the VAA's payload in the native format of their chains.
+func printAddresses(v *vaa.VAA) {
+	printAddress := func(name string, c vaa.ChainID, a vaa.Address) {
+		fmt.Printf("%s (%s): %s\n", name, c, a.NativeString(c))
+	}
+	printTransfer := func(t *vaa.BodyTransfer) {
+		printAddress("source_address", t.SourceChain, t.SourceAddress)
+		printAddress("target_address", t.TargetChain, t.TargetAddress)
+		printAddress("asset_address", t.Asset.Chain, t.Asset.Address)
+	}
+
+	switch p := v.Payload.(type) {
+	case *vaa.BodyTransfer:
+		printTransfer(p)
+	case *vaa.BodyTransferWithPayload:
+		printTransfer(&p.BodyTransfer)
+	case *vaa.BodyMessage:
+		printAddress("emitter_address", p.EmitterChain, p.EmitterAddress)
+	case *vaa.BodyContractUpgrade:
+		printAddress("new_contract", vaa.ChainID(p.ChainID), p.NewContract)
+	}
+}
+
+// printSignatureReport verifies the VAA's signatures against the given guardian addresses and prints the result.
+func printSignatureReport(v *vaa.VAA, guardians []string) {
+	gs := &common.GuardianSet{Index: v.GuardianSetIndex}
+	for _, g := range guardians {
+		if !ethcommon.IsHexAddress(g) {
+			log.Fatalf("invalid guardian address: %s", g)
+		}
+		gs.Keys = append(gs.Keys, ethcommon.HexToAddress(g))
+	}
+
+	r, err := common.VerifySignatures(v, gs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, s := range r.Signatures {
+		fmt.Printf("signature %d: %s (signer %s)\n", s.Index, s.Status, s.Signer.Hex())
+	}
+	fmt.Printf("%d of %d required signatures valid, quorum met: %v\n", r.ValidSignatures, r.Quorum, r.QuorumMet())
+}
@@ -2,9 +2,13 @@ package guardiand
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -25,8 +29,22 @@ func init() {
 		panic(err)
 	}
 
+	AdminClientSupervisorStatusCmd.Flags().StringVar(clientSocketPath, "socket", "", "gRPC admin server socket to connect to")
+	err = cobra.MarkFlagRequired(AdminClientSupervisorStatusCmd.Flags(), "socket")
+	if err != nil {
+		panic(err)
+	}
+
+	AdminClientVAATimelineCmd.Flags().StringVar(clientSocketPath, "socket", "", "gRPC admin server socket to connect to")
+	err = cobra.MarkFlagRequired(AdminClientVAATimelineCmd.Flags(), "socket")
+	if err != nil {
+		panic(err)
+	}
+
 	AdminCmd.AddCommand(AdminClientInjectGuardianSetUpdateCmd)
 	AdminCmd.AddCommand(AdminClientGovernanceVAAVerifyCmd)
+	AdminCmd.AddCommand(AdminClientSupervisorStatusCmd)
+	AdminCmd.AddCommand(AdminClientVAATimelineCmd)
 }
 
 var AdminCmd = &cobra.Command{
@@ -41,6 +59,20 @@ var AdminClientInjectGuardianSetUpdateCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 }
 
+var AdminClientSupervisorStatusCmd = &cobra.Command{
+	Use:   "supervisor-status",
+	Short: "Show the status and restart statistics of all supervised runnables",
+	Run:   runSupervisorStatus,
+	Args:  cobra.NoArgs,
+}
+
+var AdminClientVAATimelineCmd = &cobra.Command{
+	Use:   "vaa-timeline [DIGEST]",
+	Short: "Show when the node and each guardian observed a VAA, and when it reached quorum",
+	Run:   runVAATimeline,
+	Args:  cobra.ExactArgs(1),
+}
+
 func getAdminClient(ctx context.Context, addr string) (*grpc.ClientConn, error, nodev1.NodePrivilegedClient) {
 	conn, err := grpc.DialContext(ctx, fmt.Sprintf("unix:///%s", addr), grpc.WithInsecure())
 
@@ -78,3 +110,78 @@ func runInjectGovernanceVAA(cmd *cobra.Command, args []string) {
 
 	log.Printf("VAA successfully injected with digest %s", hexutils.BytesToHex(resp.Digest))
 }
+
+func runSupervisorStatus(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err, c := getAdminClient(ctx, *clientSocketPath)
+	if err != nil {
+		log.Fatalf("failed to get admin client: %v", err)
+	}
+	defer conn.Close()
+
+	resp, err := c.GetSupervisorStatus(ctx, &nodev1.GetSupervisorStatusRequest{})
+	if err != nil {
+		log.Fatalf("failed to get supervisor status: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "DN\tSTATE\tRESTARTS\tBACKOFF\tLAST ERROR")
+	for _, n := range resp.Nodes {
+		lastErr := "-"
+		if n.LastErrorTimestamp != 0 {
+			lastErr = fmt.Sprintf("%s: %s", time.Unix(n.LastErrorTimestamp, 0).Format(time.RFC3339), n.LastError)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+			n.Dn, n.State, n.Restarts, time.Duration(n.BackoffMs)*time.Millisecond, lastErr)
+	}
+	w.Flush()
+}
+
+func runVAATimeline(cmd *cobra.Command, args []string) {
+	digest, err := hex.DecodeString(strings.TrimPrefix(args[0], "0x"))
+	if err != nil {
+		log.Fatalf("invalid digest: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err, c := getAdminClient(ctx, *clientSocketPath)
+	if err != nil {
+		log.Fatalf("failed to get admin client: %v", err)
+	}
+	defer conn.Close()
+
+	resp, err := c.GetVAATimeline(ctx, &nodev1.GetVAATimelineRequest{Digest: digest})
+	if err != nil {
+		log.Fatalf("failed to get VAA timeline: %v", err)
+	}
+
+	// Offsets are relative to the first time the digest was seen.
+	start := time.Unix(0, resp.FirstObservedMs*int64(time.Millisecond))
+	offset := func(ms int64) string {
+		if ms == 0 {
+			return "-"
+		}
+		return time.Unix(0, ms*int64(time.Millisecond)).Sub(start).String()
+	}
+
+	gs := "unknown"
+	if resp.HasGuardianSet {
+		gs = fmt.Sprintf("%d", resp.GuardianSetIndex)
+	}
+	fmt.Printf("source: %s, guardian set: %s, first observed: %s\n\n",
+		resp.Source, gs, start.Format(time.RFC3339Nano))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "EVENT\tOFFSET")
+	fmt.Fprintf(w, "our observation\t%s\n", offset(resp.OurObservationMs))
+	for _, s := range resp.Signatures {
+		fmt.Fprintf(w, "signature %s\t%s\n", s.GuardianAddr, offset(s.TimestampMs))
+	}
+	fmt.Fprintf(w, "quorum\t%s\n", offset(resp.QuorumMs))
+	fmt.Fprintf(w, "submitted\t%s\n", offset(resp.SubmittedMs))
+	w.Flush()
+}
@@ -2,11 +2,13 @@ package guardiand
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
 	"net"
 	"os"
+	"sync/atomic"
 	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -16,6 +18,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/processor"
 	nodev1 "github.com/certusone/wormhole/bridge/pkg/proto/node/v1"
 	"github.com/certusone/wormhole/bridge/pkg/supervisor"
 	"github.com/certusone/wormhole/bridge/pkg/vaa"
@@ -23,8 +26,13 @@ import (
 
 type nodePrivilegedService struct {
 	nodev1.UnimplementedNodePrivilegedServer
-	injectC chan<- *vaa.VAA
-	logger  *zap.Logger
+	injectC   chan<- *vaa.VAA
+	timelineC chan<- *processor.TimelineRequest
+	logger    *zap.Logger
+
+	// supervisorStatus holds a func() []supervisor.NodeStatus returning a snapshot of the supervision tree. It's set
+	// once the admin service runnable starts, since the supervision tree is only reachable from within a runnable.
+	supervisorStatus atomic.Value
 }
 
 // adminGuardianSetUpdateToVAA converts a nodev1.GuardianSetUpdate message to its canonical VAA representation.
@@ -78,9 +86,24 @@ func adminContractUpgradeToVAA(req *nodev1.ContractUpgrade, guardianSetIndex uin
 		return nil, errors.New("invalid chain_id")
 	}
 
+	chainID := vaa.ChainID(req.ChainId)
+	switch chainID {
+	case vaa.ChainIDSolana, vaa.ChainIDEthereum, vaa.ChainIDTerra, vaa.ChainIDQtum:
+	default:
+		return nil, fmt.Errorf("unsupported chain_id %d", req.ChainId)
+	}
+
 	newContractAddress := vaa.Address{}
 	copy(newContractAddress[:], req.NewContract)
 
+	if newContractAddress == (vaa.Address{}) {
+		return nil, errors.New("invalid new_contract address: zero address")
+	}
+	// Contracts on chains with shorter addresses are left-padded to 32 bytes.
+	if _, err := vaa.FormatAddress(chainID, newContractAddress); err != nil {
+		return nil, fmt.Errorf("invalid new_contract address for %s: %w", chainID, err)
+	}
+
 	v := &vaa.VAA{
 		Version:          vaa.SupportedVAAVersion,
 		GuardianSetIndex: guardianSetIndex,
@@ -94,6 +117,29 @@ func adminContractUpgradeToVAA(req *nodev1.ContractUpgrade, guardianSetIndex uin
 	return v, nil
 }
 
+// adminBridgePauseToVAA converts a nodev1.BridgePause message to its canonical VAA representation.
+// Returns an error if the data is invalid.
+func adminBridgePauseToVAA(req *nodev1.BridgePause, guardianSetIndex uint32, timestamp uint32) (*vaa.VAA, error) {
+	if req.ChainId > math.MaxUint8 {
+		return nil, errors.New("invalid chain_id")
+	}
+	if !vaa.ChainID(req.ChainId).IsKnown() {
+		return nil, fmt.Errorf("unsupported chain_id %d", req.ChainId)
+	}
+
+	v := &vaa.VAA{
+		Version:          vaa.SupportedVAAVersion,
+		GuardianSetIndex: guardianSetIndex,
+		Timestamp:        time.Unix(int64(timestamp), 0),
+		Payload: &vaa.BodyBridgePause{
+			ChainID: vaa.ChainID(req.ChainId),
+			Paused:  req.Paused,
+		},
+	}
+
+	return v, nil
+}
+
 func (s *nodePrivilegedService) InjectGovernanceVAA(ctx context.Context, req *nodev1.InjectGovernanceVAARequest) (*nodev1.InjectGovernanceVAAResponse, error) {
 	s.logger.Info("governance VAA injected via admin socket", zap.String("request", req.String()))
 
@@ -106,6 +152,8 @@ func (s *nodePrivilegedService) InjectGovernanceVAA(ctx context.Context, req *no
 		v, err = adminGuardianSetUpdateToVAA(payload.GuardianSet, req.CurrentSetIndex, req.Timestamp)
 	case *nodev1.InjectGovernanceVAARequest_ContractUpgrade:
 		v, err = adminContractUpgradeToVAA(payload.ContractUpgrade, req.CurrentSetIndex, req.Timestamp)
+	case *nodev1.InjectGovernanceVAARequest_BridgePause:
+		v, err = adminBridgePauseToVAA(payload.BridgePause, req.CurrentSetIndex, req.Timestamp)
 	default:
 		panic(fmt.Sprintf("unsupported VAA type: %T", payload))
 	}
@@ -129,7 +177,82 @@ func (s *nodePrivilegedService) InjectGovernanceVAA(ctx context.Context, req *no
 	return &nodev1.InjectGovernanceVAAResponse{Digest: digest.Bytes()}, nil
 }
 
-func adminServiceRunnable(logger *zap.Logger, socketPath string, injectC chan<- *vaa.VAA) (supervisor.Runnable, error) {
+func (s *nodePrivilegedService) GetSupervisorStatus(ctx context.Context, req *nodev1.GetSupervisorStatusRequest) (*nodev1.GetSupervisorStatusResponse, error) {
+	supervisorStatus, ok := s.supervisorStatus.Load().(func() []supervisor.NodeStatus)
+	if !ok {
+		return nil, status.Error(codes.Unavailable, "supervisor not yet available")
+	}
+
+	snapshot := supervisorStatus()
+	nodes := make([]*nodev1.SupervisorNode, len(snapshot))
+	for i, n := range snapshot {
+		nodes[i] = &nodev1.SupervisorNode{
+			Dn:        n.DN,
+			State:     n.State,
+			Restarts:  n.Restarts,
+			LastError: n.LastError,
+			BackoffMs: n.Backoff.Milliseconds(),
+		}
+		if n.LastErrorTime != nil {
+			nodes[i].LastErrorTimestamp = n.LastErrorTime.Unix()
+		}
+	}
+
+	return &nodev1.GetSupervisorStatusResponse{Nodes: nodes}, nil
+}
+
+func (s *nodePrivilegedService) GetVAATimeline(ctx context.Context, req *nodev1.GetVAATimelineRequest) (*nodev1.GetVAATimelineResponse, error) {
+	if len(req.Digest) != 32 {
+		return nil, status.Error(codes.InvalidArgument, "invalid digest")
+	}
+
+	respC := make(chan *processor.Timeline, 1)
+	select {
+	case s.timelineC <- &processor.TimelineRequest{Digest: hex.EncodeToString(req.Digest), ResponseC: respC}:
+	case <-ctx.Done():
+		return nil, status.Errorf(codes.Unavailable, "processor did not respond: %v", ctx.Err())
+	}
+
+	var t *processor.Timeline
+	select {
+	case t = <-respC:
+	case <-ctx.Done():
+		return nil, status.Errorf(codes.Unavailable, "processor did not respond: %v", ctx.Err())
+	}
+	if t == nil {
+		return nil, status.Error(codes.NotFound, "unknown digest")
+	}
+
+	resp := &nodev1.GetVAATimelineResponse{
+		FirstObservedMs:  unixMillis(t.FirstObserved),
+		OurObservationMs: unixMillis(t.OurObservation),
+		QuorumMs:         unixMillis(t.Quorum),
+		SubmittedMs:      unixMillis(t.Submitted),
+		Source:           t.Source,
+	}
+	for _, sig := range t.Signatures {
+		resp.Signatures = append(resp.Signatures, &nodev1.GetVAATimelineResponse_Signature{
+			GuardianAddr: sig.Guardian.Hex(),
+			TimestampMs:  unixMillis(sig.Time),
+		})
+	}
+	if t.GuardianSetIndex != nil {
+		resp.HasGuardianSet = true
+		resp.GuardianSetIndex = *t.GuardianSetIndex
+	}
+
+	return resp, nil
+}
+
+// unixMillis returns t as UNIX timestamp (ms), or zero if t is the zero time.
+func unixMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func adminServiceRunnable(logger *zap.Logger, socketPath string, injectC chan<- *vaa.VAA, timelineC chan<- *processor.TimelineRequest) (supervisor.Runnable, error) {
 	// Delete existing UNIX socket, if present.
 	fi, err := os.Stat(socketPath)
 	if err == nil {
@@ -159,11 +282,20 @@ func adminServiceRunnable(logger *zap.Logger, socketPath string, injectC chan<-
 	logger.Info("admin server listening on", zap.String("path", socketPath))
 
 	nodeService := &nodePrivilegedService{
-		injectC: injectC,
-		logger:  logger.Named("adminservice"),
+		injectC:   injectC,
+		timelineC: timelineC,
+		logger:    logger.Named("adminservice"),
 	}
 
 	grpcServer := grpc.NewServer()
 	nodev1.RegisterNodePrivilegedServer(grpcServer, nodeService)
-	return supervisor.GRPCServer(grpcServer, l, false), nil
+	serve := supervisor.GRPCServer(grpcServer, l, false)
+
+	return func(ctx context.Context) error {
+		// Set before the server starts serving requests.
+		nodeService.supervisorStatus.Store(func() []supervisor.NodeStatus {
+			return supervisor.Snapshot(ctx)
+		})
+		return serve(ctx)
+	}, nil
 }
@@ -0,0 +1,97 @@
+package guardiand
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nodev1 "github.com/certusone/wormhole/bridge/pkg/proto/node/v1"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+func TestAdminContractUpgradeToVAA(t *testing.T) {
+	// A 20-byte address, left-padded to 32 bytes.
+	padded := append(make([]byte, 12), bytes.Repeat([]byte{0xab}, 20)...)
+	// A full 32-byte address, only valid on Solana.
+	full := bytes.Repeat([]byte{0xab}, 32)
+
+	tests := []struct {
+		name    string
+		chainID uint32
+		addr    []byte
+		wantErr string
+	}{
+		{name: "solana", chainID: vaa.ChainIDSolana, addr: full},
+		{name: "ethereum", chainID: vaa.ChainIDEthereum, addr: padded},
+		{name: "terra", chainID: vaa.ChainIDTerra, addr: padded},
+		{name: "qtum", chainID: vaa.ChainIDQtum, addr: padded},
+		{name: "unsupported chain", chainID: 0xfe, addr: padded, wantErr: "unsupported chain_id 254"},
+		{name: "chain out of range", chainID: 256, addr: padded, wantErr: "invalid chain_id"},
+		{name: "unpadded 20-byte address", chainID: vaa.ChainIDEthereum, addr: padded[12:],
+			wantErr: "invalid new_contract address"},
+		{name: "too long address", chainID: vaa.ChainIDSolana, addr: append(full, 0),
+			wantErr: "invalid new_contract address"},
+		{name: "zero address", chainID: vaa.ChainIDEthereum, addr: make([]byte, 32),
+			wantErr: "invalid new_contract address: zero address"},
+		{name: "ethereum address without padding", chainID: vaa.ChainIDEthereum, addr: full,
+			wantErr: "invalid new_contract address for ethereum"},
+		{name: "terra address without padding", chainID: vaa.ChainIDTerra, addr: full,
+			wantErr: "invalid new_contract address for terra"},
+		{name: "qtum address without padding", chainID: vaa.ChainIDQtum, addr: full,
+			wantErr: "invalid new_contract address for qtum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &nodev1.ContractUpgrade{ChainId: tt.chainID, NewContract: tt.addr}
+			v, err := adminContractUpgradeToVAA(req, 3, 1605105600)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var want vaa.Address
+			copy(want[:], tt.addr)
+			assert.Equal(t, uint32(3), v.GuardianSetIndex)
+			assert.Equal(t, time.Unix(1605105600, 0), v.Timestamp)
+			assert.Equal(t, &vaa.BodyContractUpgrade{ChainID: uint8(tt.chainID), NewContract: want}, v.Payload)
+		})
+	}
+}
+
+func TestAdminBridgePauseToVAA(t *testing.T) {
+	tests := []struct {
+		name    string
+		chainID uint32
+		paused  bool
+		wantErr string
+	}{
+		{name: "pause", chainID: vaa.ChainIDEthereum, paused: true},
+		{name: "unpause", chainID: vaa.ChainIDTerra, paused: false},
+		{name: "zero chain", chainID: 0, paused: true, wantErr: "unsupported chain_id 0"},
+		{name: "unknown chain", chainID: 0xfe, paused: true, wantErr: "unsupported chain_id 254"},
+		{name: "chain out of range", chainID: 256, paused: true, wantErr: "invalid chain_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &nodev1.BridgePause{ChainId: tt.chainID, Paused: tt.paused}
+			v, err := adminBridgePauseToVAA(req, 3, 1605105600)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, uint32(3), v.GuardianSetIndex)
+			assert.Equal(t, time.Unix(1605105600, 0), v.Timestamp)
+			assert.Equal(t, &vaa.BodyBridgePause{ChainID: vaa.ChainID(tt.chainID), Paused: tt.paused}, v.Payload)
+		})
+	}
+}
@@ -11,17 +11,22 @@ import (
 
 	"github.com/certusone/wormhole/bridge/pkg/devnet"
 	nodev1 "github.com/certusone/wormhole/bridge/pkg/proto/node/v1"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
 )
 
 var setUpdateNumGuardians *int
 var templateGuardianIndex *int
+var contractUpgradeChain *string
 
 func init() {
 	templateGuardianIndex = TemplateCmd.PersistentFlags().Int("idx", 0, "Default current guardian set index")
 	setUpdateNumGuardians = AdminClientGuardianSetTemplateCmd.Flags().Int("num", 1, "Number of devnet guardians in example file")
+	contractUpgradeChain = AdminClientContractUpgradeTemplateCmd.Flags().String("chain", "solana",
+		"Chain to upgrade the bridge contract on (solana, ethereum, terra or qtum)")
 
 	TemplateCmd.AddCommand(AdminClientGuardianSetTemplateCmd)
 	TemplateCmd.AddCommand(AdminClientContractUpgradeTemplateCmd)
+	TemplateCmd.AddCommand(AdminClientBridgePauseTemplateCmd)
 }
 
 var TemplateCmd = &cobra.Command{
@@ -43,6 +48,13 @@ var AdminClientContractUpgradeTemplateCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 }
 
+var AdminClientBridgePauseTemplateCmd = &cobra.Command{
+	Use:   "bridge-pause [FILENAME]",
+	Short: "Generate an empty bridge pause template at specified path (offline)",
+	Run:   runBridgePauseTemplate,
+	Args:  cobra.ExactArgs(1),
+}
+
 func runGuardianSetTemplate(cmd *cobra.Command, args []string) {
 	path := args[0]
 
@@ -80,6 +92,33 @@ func runGuardianSetTemplate(cmd *cobra.Command, args []string) {
 func runContractUpgradeTemplate(cmd *cobra.Command, args []string) {
 	path := args[0]
 
+	chain, err := vaa.ChainIDFromString(*contractUpgradeChain)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Use the devnet bridge contracts as examples, such that this doubles as a test fixture.
+	var example string
+	switch chain {
+	case vaa.ChainIDSolana:
+		example = devnet.SolanaBridgeContract
+	case vaa.ChainIDEthereum:
+		example = devnet.GanacheBridgeContractAddress.Hex()
+	case vaa.ChainIDTerra:
+		example = devnet.TerraBridgeAddress
+	case vaa.ChainIDQtum:
+		// The devnet Qtum bridge is deployed at runtime, so there's no well-known address to use. This is an
+		// obviously fake placeholder - replace it with the new contract's address.
+		example = "ffffffffffffffffffffffffffffffffffffffff"
+	default:
+		log.Fatalf("contract upgrades are not supported on %s", chain)
+	}
+
+	newContract, err := vaa.ParseAddress(chain, example)
+	if err != nil {
+		panic(err)
+	}
+
 	m := &nodev1.InjectGovernanceVAARequest{
 		CurrentSetIndex: uint32(*templateGuardianIndex),
 		// Timestamp is hardcoded to make it reproducible on different devnet nodes.
@@ -87,8 +126,35 @@ func runContractUpgradeTemplate(cmd *cobra.Command, args []string) {
 		Timestamp: 1605744545,
 		Payload: &nodev1.InjectGovernanceVAARequest_ContractUpgrade{
 			ContractUpgrade: &nodev1.ContractUpgrade{
-				ChainId:     1,
-				NewContract: make([]byte, 32),
+				ChainId:     uint32(chain),
+				NewContract: newContract[:],
+			},
+		},
+	}
+
+	b, err := prototext.MarshalOptions{Multiline: true}.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+
+	err = ioutil.WriteFile(path, b, 0640)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runBridgePauseTemplate(cmd *cobra.Command, args []string) {
+	path := args[0]
+
+	m := &nodev1.InjectGovernanceVAARequest{
+		CurrentSetIndex: uint32(*templateGuardianIndex),
+		// Timestamp is hardcoded to make it reproducible on different devnet nodes.
+		// In production, a real UNIX timestamp should be used (see node.proto).
+		Timestamp: 1605744545,
+		Payload: &nodev1.InjectGovernanceVAARequest_BridgePause{
+			BridgePause: &nodev1.BridgePause{
+				ChainId: vaa.ChainIDEthereum,
+				Paused:  true,
 			},
 		},
 	}
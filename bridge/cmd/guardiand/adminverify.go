@@ -41,6 +41,10 @@ func runGovernanceVAAVerify(cmd *cobra.Command, args []string) {
 		v, err = adminGuardianSetUpdateToVAA(payload.GuardianSet, msg.CurrentSetIndex, msg.Timestamp)
 	case *nodev1.InjectGovernanceVAARequest_ContractUpgrade:
 		v, err = adminContractUpgradeToVAA(payload.ContractUpgrade, msg.CurrentSetIndex, msg.Timestamp)
+	case *nodev1.InjectGovernanceVAARequest_BridgePause:
+		v, err = adminBridgePauseToVAA(payload.BridgePause, msg.CurrentSetIndex, msg.Timestamp)
+	default:
+		log.Fatalf("unsupported payload type: %T", payload)
 	}
 	if err != nil {
 		log.Fatalf("invalid update: %v", err)
@@ -52,4 +56,9 @@ func runGovernanceVAAVerify(cmd *cobra.Command, args []string) {
 	}
 
 	log.Printf("VAA with digest %s: %+v", digest.Hex(), spew.Sdump(v))
+
+	if u, ok := v.Payload.(*vaa.BodyContractUpgrade); ok {
+		c := vaa.ChainID(u.ChainID)
+		log.Printf("new contract on %s: %s", c, u.NewContract.NativeString(c))
+	}
 }
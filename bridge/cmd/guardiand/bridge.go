@@ -2,11 +2,13 @@ package guardiand
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"syscall"
+	"time"
 
 	"github.com/certusone/wormhole/bridge/pkg/qtum"
 
@@ -19,12 +21,15 @@ import (
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"golang.org/x/sys/unix"
 
 	"github.com/certusone/wormhole/bridge/pkg/common"
 	"github.com/certusone/wormhole/bridge/pkg/devnet"
 	"github.com/certusone/wormhole/bridge/pkg/ethereum"
+	"github.com/certusone/wormhole/bridge/pkg/fake"
 	"github.com/certusone/wormhole/bridge/pkg/p2p"
 	"github.com/certusone/wormhole/bridge/pkg/processor"
 	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
@@ -39,96 +44,73 @@ import (
 	ipfslog "github.com/ipfs/go-log/v2"
 )
 
-var (
-	p2pNetworkID *string
-	p2pPort      *uint
-	p2pBootstrap *string
-
-	nodeKeyPath *string
-
-	adminSocketPath *string
-
-	statusAddr *string
-
-	bridgeKeyPath       *string
-	solanaBridgeAddress *string
-
-	ethRPC           *string
-	ethContract      *string
-	ethConfirmations *uint64
-
-	qtumSupport       *bool
-	qtumRPC           *string
-	qtumContract      *string
-	qtumChainID       *string
-	qtumConfirmations *uint64
-	qtumKeyPath       *string
-
-	terraSupport  *bool
-	terraWS       *string
-	terraLCD      *string
-	terraChainID  *string
-	terraContract *string
-	terraKeyPath  *string
-
-	solanaWsRPC *string
-	solanaRPC   *string
-
-	agentRPC *string
-
-	logLevel *string
-
-	unsafeDevMode   *bool
-	devNumGuardians *uint
-	nodeName        *string
-
-	publicRPC *string
-)
-
 func init() {
-	p2pNetworkID = BridgeCmd.Flags().String("network", "/wormhole/dev", "P2P network identifier")
-	p2pPort = BridgeCmd.Flags().Uint("port", 8999, "P2P UDP listener port")
-	p2pBootstrap = BridgeCmd.Flags().String("bootstrap", "", "P2P bootstrap peers (comma-separated)")
-
-	statusAddr = BridgeCmd.Flags().String("statusAddr", "Listen address for status server (disabled if blank)", "[::1]:6060")
-
-	nodeKeyPath = BridgeCmd.Flags().String("nodeKey", "", "Path to node key (will be generated if it doesn't exist)")
-
-	adminSocketPath = BridgeCmd.Flags().String("adminSocket", "", "Admin gRPC service UNIX domain socket path")
-
-	bridgeKeyPath = BridgeCmd.Flags().String("bridgeKey", "", "Path to guardian key (required)")
-	solanaBridgeAddress = BridgeCmd.Flags().String("solanaBridgeAddress", "", "Address of the Solana Bridge Program (required)")
-
-	ethRPC = BridgeCmd.Flags().String("ethRPC", "", "Ethereum RPC URL")
-	ethContract = BridgeCmd.Flags().String("ethContract", "", "Ethereum bridge contract address")
-	ethConfirmations = BridgeCmd.Flags().Uint64("ethConfirmations", 15, "Ethereum confirmation count requirement")
-
-	qtumSupport = BridgeCmd.Flags().Bool("qtum", false, "Turn on support for Qtum")
-	qtumRPC = BridgeCmd.Flags().String("qtumRPC", "", "Qtum RPC URL")
-	qtumContract = BridgeCmd.Flags().String("qtumContract", "", "Qtum bridge contract address")
-	qtumConfirmations = BridgeCmd.Flags().Uint64("qtumConfirmations", 6, "Qtum confirmation count requirement")
-	qtumChainID = BridgeCmd.Flags().String("qtumChainID", "", "Qtum chain ID, used in client")
-	qtumKeyPath = BridgeCmd.Flags().String("qtumKey", "", "Path to wif for account paying gas for submitting transactions to Qtum")
-
-	terraSupport = BridgeCmd.Flags().Bool("terra", false, "Turn on support for Terra")
-	terraWS = BridgeCmd.Flags().String("terraWS", "", "Path to terrad root for websocket connection")
-	terraLCD = BridgeCmd.Flags().String("terraLCD", "", "Path to LCD service root for http calls")
-	terraChainID = BridgeCmd.Flags().String("terraChainID", "", "Terra chain ID, used in LCD client initialization")
-	terraContract = BridgeCmd.Flags().String("terraContract", "", "Wormhole contract address on Terra blockchain")
-	terraKeyPath = BridgeCmd.Flags().String("terraKey", "", "Path to mnemonic for account paying gas for submitting transactions to Terra")
-
-	solanaWsRPC = BridgeCmd.Flags().String("solanaWS", "", "Solana Websocket URL (required")
-	solanaRPC = BridgeCmd.Flags().String("solanaRPC", "", "Solana RPC URL (required")
-
-	agentRPC = BridgeCmd.Flags().String("agentRPC", "", "Solana agent sidecar gRPC socket path")
-
-	logLevel = BridgeCmd.Flags().String("logLevel", "info", "Logging level (debug, info, warn, error, dpanic, panic, fatal)")
-
-	unsafeDevMode = BridgeCmd.Flags().Bool("unsafeDevMode", false, "Launch node in unsafe, deterministic devnet mode")
-	devNumGuardians = BridgeCmd.Flags().Uint("devNumGuardians", 5, "Number of devnet guardians to include in guardian set")
-	nodeName = BridgeCmd.Flags().String("nodeName", "", "Node name to announce in gossip heartbeats")
+	addBridgeFlags(BridgeCmd.Flags())
+}
 
-	publicRPC = BridgeCmd.Flags().String("publicRPC", "", "Listen address for public gRPC interface")
+// addBridgeFlags defines the bridge command line flags on fs. Their config keys are listed in bridgeFlagKeys.
+func addBridgeFlags(fs *pflag.FlagSet) {
+	fs.String("network", "/wormhole/dev", "P2P network identifier")
+	fs.Uint("port", 8999, "P2P listener port, used by the default listen addresses")
+	fs.StringSlice("p2pTransports", nil, "Enabled P2P transports (quic, tcp; default quic)")
+	fs.StringSlice("p2pSecurity", nil, "Security protocols for the P2P TCP transport in order of preference (tls, noise; default tls)")
+	fs.StringSlice("p2pListenAddrs", nil, "P2P listen multiaddrs (default: all interfaces on --port for each transport)")
+	fs.StringSlice("p2pAnnounceAddrs", nil, "P2P multiaddrs to advertise to peers instead of the listen addresses")
+	fs.Int("connMgrLowWater", p2p.DefaultConnManagerConfig.LowWater, "Number of P2P connections the connection manager trims down to")
+	fs.Int("connMgrHighWater", p2p.DefaultConnManagerConfig.HighWater, "Number of P2P connections above which the connection manager starts trimming")
+	fs.Duration("connMgrGracePeriod", p2p.DefaultConnManagerConfig.GracePeriod, "Minimum age of P2P connections before they can be trimmed")
+	fs.String("bootstrap", "", "P2P bootstrap peers (comma-separated)")
+	fs.String("peerstore", "", "Path to persistent P2P peerstore (disabled if blank)")
+	fs.Int("minPeers", 3, "Minimum number of connected P2P guardian peers before re-dialing bootstrap and known peers")
+	fs.Bool("legacyBroadcast", true, "Publish observations and heartbeats on and receive from the legacy P2P broadcast topic used by outdated nodes")
+
+	fs.String("statusAddr", "[::1]:6060", "Listen address for status server (disabled if blank)")
+	fs.Duration("statusStaleThreshold", 5*time.Minute, "Maximum time without progress before a component is reported as unhealthy")
+
+	fs.String("nodeKey", "", "Path to node key (will be generated if it doesn't exist)")
+
+	fs.String("adminSocket", "", "Admin gRPC service UNIX domain socket path")
+
+	fs.String("bridgeKey", "", "Path to guardian key (required)")
+	fs.String("solanaBridgeAddress", "", "Address of the Solana Bridge Program (required)")
+
+	fs.String("ethRPC", "", "Ethereum RPC URL")
+	fs.String("ethContract", "", "Ethereum bridge contract address")
+	fs.Uint64("ethConfirmations", 15, "Ethereum confirmation count requirement")
+
+	fs.Bool("qtum", false, "Turn on support for Qtum")
+	fs.String("qtumRPC", "", "Qtum RPC URL")
+	fs.String("qtumContract", "", "Qtum bridge contract address")
+	fs.Uint64("qtumConfirmations", 6, "Qtum confirmation count requirement")
+	fs.String("qtumChainID", "", "Qtum chain ID, used in client")
+	fs.String("qtumKey", "", "Path to wif for account paying gas for submitting transactions to Qtum")
+
+	fs.Bool("terra", false, "Turn on support for Terra")
+	fs.String("terraWS", "", "Path to terrad root for websocket connection")
+	fs.String("terraLCD", "", "Path to LCD service root for http calls")
+	fs.String("terraChainID", "", "Terra chain ID, used in LCD client initialization")
+	fs.String("terraContract", "", "Wormhole contract address on Terra blockchain")
+	fs.String("terraKey", "", "Path to mnemonic for account paying gas for submitting transactions to Terra")
+
+	fs.Bool("fake", false, "Emit synthetic lockups and messages for load testing (requires --unsafeDevMode)")
+	fs.Float64("fakeRate", 1, "Synthetic events per second")
+	fs.StringSlice("fakeRoutes", nil, "Source and target chains of synthetic lockups as <source>:<target>[=<weight>], or <source>:message[=<weight>] for messages (default ethereum:solana,solana:ethereum)")
+	fs.Int64("fakeSeed", 0, "Seed of synthetic events, must be the same on all guardians")
+
+	fs.String("solanaWS", "", "Solana Websocket URL (required)")
+	fs.String("solanaRPC", "", "Solana RPC URL (required)")
+
+	fs.String("agentRPC", "", "Solana agent sidecar gRPC socket path")
+
+	fs.String("logLevel", "info", "Logging level (debug, info, warn, error, dpanic, panic, fatal)")
+
+	fs.Bool("unsafeDevMode", false, "Launch node in unsafe, deterministic devnet mode")
+	fs.Uint("devNumGuardians", 5, "Number of devnet guardians to include in guardian set")
+	fs.Bool("batchTransfers", false, "Additionally sign lockups confirmed in the same block as a single batch VAA, on chains that support it")
+	fs.String("pauseState", "", "Path to persist the bridge pause state to, such that it survives restarts (optional in --unsafeDevMode)")
+	fs.String("nodeName", "", "Node name to announce in gossip heartbeats")
+
+	fs.String("publicRPC", "", "Listen address for public gRPC interface")
 }
 
 var (
@@ -149,8 +131,8 @@ const devwarning = `
 
 `
 
-func rootLoggerName() string {
-	if *unsafeDevMode {
+func rootLoggerName(unsafeDevMode bool) string {
+	if unsafeDevMode {
 		// FIXME: add hostname to root logger for cleaner console output in multi-node development.
 		// The proper way is to change the output format to include the hostname.
 		hostname, err := os.Hostname()
@@ -189,7 +171,13 @@ var BridgeCmd = &cobra.Command{
 }
 
 func runBridge(cmd *cobra.Command, args []string) {
-	if *unsafeDevMode {
+	cfg, err := loadBridgeConfig(viper.GetViper(), cmd.Flags())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if cfg.UnsafeDevMode {
 		fmt.Print(devwarning)
 	}
 
@@ -198,168 +186,53 @@ func runBridge(cmd *cobra.Command, args []string) {
 
 	// Set up logging. The go-log zap wrapper that libp2p uses is compatible with our
 	// usage of zap in supervisor, which is nice.
-	lvl, err := ipfslog.LevelFromString(*logLevel)
+	lvl, err := ipfslog.LevelFromString(cfg.LogLevel)
 	if err != nil {
 		fmt.Println("Invalid log level")
 		os.Exit(1)
 	}
 
 	// Our root logger. Convert directly to a regular Zap logger.
-	logger := ipfslog.Logger(rootLoggerName()).Desugar()
+	logger := ipfslog.Logger(rootLoggerName(cfg.UnsafeDevMode)).Desugar()
 
 	// Override the default go-log config, which uses a magic environment variable.
 	ipfslog.SetAllLoggers(lvl)
 
-	// Register components for readiness checks.
-	readiness.RegisterComponent(common.ReadinessEthSyncing)
-	readiness.RegisterComponent(common.ReadinessSolanaSyncing)
-	if *terraSupport {
-		readiness.RegisterComponent(common.ReadinessTerraSyncing)
-	}
-
-	if *qtumSupport {
-		readiness.RegisterComponent(common.ReadinessQtumSyncing)
-	}
-
-	if *statusAddr != "" {
-		// Use a custom routing instead of using http.DefaultServeMux directly to avoid accidentally exposing packages
-		// that register themselves with it by default (like pprof).
-		router := mux.NewRouter()
-
-		// pprof server. NOT necessarily safe to expose publicly - only enable it in dev mode to avoid exposing it by
-		// accident. There's benefit to having pprof enabled on production nodes, but we would likely want to expose it
-		// via a dedicated port listening on localhost, or via the admin UNIX socket.
-		if *unsafeDevMode {
-			// Pass requests to http.DefaultServeMux, which pprof automatically registers with as an import side-effect.
-			router.PathPrefix("/debug/pprof/").Handler(http.DefaultServeMux)
-		}
-
-		// Simple endpoint exposing node readiness (safe to expose to untrusted clients)
-		router.HandleFunc("/readyz", readiness.Handler)
-
-		// Prometheus metrics (safe to expose to untrusted clients)
-		router.Handle("/metrics", promhttp.Handler())
-
-		go func() {
-			logger.Info("status server listening on [::]:6060")
-			logger.Error("status server crashed", zap.Error(http.ListenAndServe("[::]:6060", router)))
-		}()
+	if f := viper.ConfigFileUsed(); f != "" {
+		logger.Info("loaded config file", zap.String("path", f))
 	}
 
 	// In devnet mode, we automatically set a number of flags that rely on deterministic keys.
-	if *unsafeDevMode {
-		g0key, err := peer.IDFromPrivateKey(devnet.DeterministicP2PPrivKeyByIndex(0))
-		if err != nil {
-			panic(err)
-		}
-
-		// Use the first guardian node as bootstrap
-		*p2pBootstrap = fmt.Sprintf("/dns4/guardian-0.guardian/udp/%d/quic/p2p/%s", *p2pPort, g0key.String())
-
-		// Deterministic ganache ETH devnet address.
-		*ethContract = devnet.GanacheBridgeContractAddress.Hex()
-
-		// Use the hostname as nodeName. For production, we don't want to do this to
-		// prevent accidentally leaking sensitive hostnames.
-		hostname, err := os.Hostname()
-		if err != nil {
-			panic(err)
-		}
-		*nodeName = hostname
+	if err := cfg.applyDevModeDefaults(); err != nil {
+		logger.Fatal("failed to apply devnet defaults", zap.Error(err))
 	}
 
-	// Verify flags
-
-	if *nodeKeyPath == "" && !*unsafeDevMode { // In devnet mode, keys are deterministically generated.
-		logger.Fatal("Please specify --nodeKey")
-	}
-	if *bridgeKeyPath == "" {
-		logger.Fatal("Please specify --bridgeKey")
-	}
-	if *adminSocketPath == "" {
-		logger.Fatal("Please specify --adminSocket")
-	}
-	if *agentRPC == "" {
-		logger.Fatal("Please specify --agentRPC")
-	}
-	if *ethRPC == "" {
-		logger.Fatal("Please specify --ethRPC")
-	}
-	if *ethContract == "" {
-		logger.Fatal("Please specify --ethContract")
+	// Verify config
+	if err := cfg.Validate(); err != nil {
+		logger.Fatal(err.Error())
 	}
-	if *nodeName == "" {
-		logger.Fatal("Please specify --nodeName")
-	}
-
-	if *qtumSupport {
 
-		if *qtumRPC == "" {
-			logger.Fatal("Please specify --qtumRPC")
-		}
-
-		if *qtumContract == "" {
-			logger.Fatal("Please specify --qtumContract")
-		}
-
-		if *qtumKeyPath == "" {
-			logger.Fatal("Please specify --qtumKeyPath")
-		}
-
-		if *qtumChainID == "" {
-			logger.Fatal("Please specify --qtumChainID")
-		}
-	}
-
-	if *solanaBridgeAddress == "" {
-		logger.Fatal("Please specify --solanaBridgeAddress")
-	}
-	if *solanaWsRPC == "" {
-		logger.Fatal("Please specify --solanaWsUrl")
-	}
-	if *solanaRPC == "" {
-		logger.Fatal("Please specify --solanaUrl")
-	}
-
-	if *terraSupport {
-		if *terraWS == "" {
-			logger.Fatal("Please specify --terraWS")
-		}
-		if *terraLCD == "" {
-			logger.Fatal("Please specify --terraLCD")
-		}
-		if *terraChainID == "" {
-			logger.Fatal("Please specify --terraChainID")
-		}
-		if *terraContract == "" {
-			logger.Fatal("Please specify --terraContract")
-		}
-		if *terraKeyPath == "" {
-			logger.Fatal("Please specify --terraKey")
-		}
-	}
-
-	ethContractAddr := eth_common.HexToAddress(*ethContract)
-	solBridgeAddress, err := solana_types.PublicKeyFromBase58(*solanaBridgeAddress)
+	ethContractAddr := eth_common.HexToAddress(cfg.Ethereum.Contract)
+	solBridgeAddress, err := solana_types.PublicKeyFromBase58(cfg.Solana.Contract)
 	if err != nil {
 		logger.Fatal("invalid Solana bridge address", zap.Error(err))
 	}
 
 	// In devnet mode, we generate a deterministic guardian key and write it to disk.
-	if *unsafeDevMode {
+	if cfg.UnsafeDevMode {
 		gk, err := generateDevnetGuardianKey()
 		if err != nil {
 			logger.Fatal("failed to generate devnet guardian key", zap.Error(err))
 		}
 
-		err = writeGuardianKey(gk, "auto-generated deterministic devnet key", *bridgeKeyPath, true)
+		err = writeGuardianKey(gk, "auto-generated deterministic devnet key", cfg.GuardianKey, true)
 		if err != nil {
 			logger.Fatal("failed to write devnet guardian key", zap.Error(err))
 		}
 	}
 
 	// Guardian key
-	gk, err := loadGuardianKey(*bridgeKeyPath)
+	gk, err := loadGuardianKey(cfg.GuardianKey, cfg.UnsafeDevMode)
 	if err != nil {
 		logger.Fatal("failed to load guardian key", zap.Error(err))
 	}
@@ -377,6 +250,13 @@ func runBridge(cmd *cobra.Command, args []string) {
 	// Ethereum lock event channel
 	lockC := make(chan *common.ChainLock)
 
+	// Lockups confirmed in the same block, sent by watchers instead of lockC if batching is enabled. The processor
+	// signs both the batch and each of its lockups.
+	lockBatchC := make(chan *common.ChainLockBatch)
+
+	// Message publications. None of the chain watchers emit them yet.
+	msgC := make(chan *common.MessagePublication)
+
 	// Ethereum incoming guardian set updates
 	setC := make(chan *common.GuardianSet)
 
@@ -392,16 +272,19 @@ func runBridge(cmd *cobra.Command, args []string) {
 	// Injected VAAs (manually generated rather than created via observation)
 	injectC := make(chan *vaa.VAA)
 
+	// Local VAA timeline queries (admin service)
+	timelineC := make(chan *processor.TimelineRequest)
+
 	// Load p2p private key
 	var priv crypto.PrivKey
-	if *unsafeDevMode {
+	if cfg.UnsafeDevMode {
 		idx, err := devnet.GetDevnetIndex()
 		if err != nil {
 			logger.Fatal("Failed to parse hostname - are we running in devnet?")
 		}
 		priv = devnet.DeterministicP2PPrivKeyByIndex(int64(idx))
 	} else {
-		priv, err = getOrCreateNodeKey(logger, *nodeKeyPath)
+		priv, err = getOrCreateNodeKey(logger, cfg.P2P.NodeKey)
 		if err != nil {
 			logger.Fatal("Failed to load node key", zap.Error(err))
 		}
@@ -409,11 +292,11 @@ func runBridge(cmd *cobra.Command, args []string) {
 
 	// Load Terra fee payer key
 	var terraFeePayer string
-	if *terraSupport {
-		if *unsafeDevMode {
-			terra.WriteDevnetKey(*terraKeyPath)
+	if cfg.Terra.Enabled {
+		if cfg.UnsafeDevMode {
+			terra.WriteDevnetKey(cfg.Terra.Key)
 		}
-		terraFeePayer, err = terra.ReadKey(*terraKeyPath)
+		terraFeePayer, err = terra.ReadKey(cfg.Terra.Key)
 		if err != nil {
 			logger.Fatal("Failed to load Terra fee payer key", zap.Error(err))
 		}
@@ -421,85 +304,162 @@ func runBridge(cmd *cobra.Command, args []string) {
 
 	// Load Qtum fee payer key
 	var qtumFeePayer string
-	if *qtumSupport {
-		if *unsafeDevMode {
-			qtum.WriteDevnetKey(*qtumKeyPath)
+	if cfg.Qtum.Enabled {
+		if cfg.UnsafeDevMode {
+			qtum.WriteDevnetKey(cfg.Qtum.Key)
 		}
-		qtumFeePayer, err = qtum.ReadKey(*qtumKeyPath)
+		qtumFeePayer, err = qtum.ReadKey(cfg.Qtum.Key)
 		if err != nil {
 			logger.Fatal("Failed to load Qtum fee payer key", zap.Error(err))
 		}
 	}
 
-	adminService, err := adminServiceRunnable(logger, *adminSocketPath, injectC)
+	// Connected chains. Ethereum and Solana are mandatory, the others are optional.
+	chains := common.NewChainRegistry()
+	chains.Register(ethereum.NewChain(cfg.Ethereum.RPC, ethContractAddr, cfg.Ethereum.Confirmations, cfg.UnsafeDevMode))
+	chains.Register(solana.NewChain(cfg.Solana.WS, cfg.Solana.RPC, solBridgeAddress))
+	if cfg.Terra.Enabled {
+		chains.Register(terra.NewChain(cfg.Terra.WS, cfg.Terra.LCD, cfg.Terra.ChainID, cfg.Terra.Contract, terraFeePayer, cfg.UnsafeDevMode))
+	}
+	if cfg.Qtum.Enabled {
+		chains.Register(qtum.NewChain(cfg.Qtum.RPC, cfg.Qtum.Contract, cfg.Qtum.ChainID, cfg.Qtum.Confirmations, qtumFeePayer, cfg.UnsafeDevMode))
+	}
+
+	// Register components for liveness checks.
+	readiness.RegisterLivenessComponent(common.LivenessP2PHeartbeat, 0)
+	readiness.RegisterLivenessComponent(common.LivenessProcessor, 0)
+
+	for _, c := range chains.All() {
+		// Register components for readiness and liveness checks.
+		readiness.RegisterComponent(c.ReadinessComponent())
+		readiness.RegisterLivenessComponent(c.ReadinessComponent(), 0)
+	}
+
+	adminService, err := adminServiceRunnable(logger, cfg.Admin.Socket, injectC, timelineC)
 	if err != nil {
 		logger.Fatal("failed to create admin service socket", zap.Error(err))
 	}
 
+	// Guardian set state managed by processor
+	gst := common.NewGuardianSetState()
+
 	// subscriber channel multiplexing for public gPRC streams
 	rawHeartbeatListeners := publicrpc.HeartbeatStreamMultiplexer(logger)
 
-	// Run supervisor.
-	supervisor.New(rootCtx, logger, func(ctx context.Context) error {
-		if err := supervisor.Run(ctx, "p2p", p2p.Run(
-			obsvC, sendC, rawHeartbeatListeners, priv, *p2pPort, *p2pNetworkID, *p2pBootstrap, *nodeName, rootCtxCancel)); err != nil {
-			return err
+	hostCfg := p2p.HostConfig{
+		Port:          cfg.P2P.Port,
+		NetworkID:     cfg.P2P.Network,
+		Transports:    cfg.P2P.Transports,
+		Security:      cfg.P2P.Security,
+		ListenAddrs:   cfg.P2P.ListenAddrs,
+		AnnounceAddrs: cfg.P2P.AnnounceAddrs,
+		ConnManager: p2p.ConnManagerConfig{
+			LowWater:    cfg.P2P.ConnMgr.LowWater,
+			HighWater:   cfg.P2P.ConnMgr.HighWater,
+			GracePeriod: cfg.P2P.ConnMgr.GracePeriod,
+		},
+	}
+
+	// Private network mode
+	if pn := cfg.P2P.PrivateNetwork(); pn != nil {
+		allowlist := make([]peer.ID, len(pn.Allowlist))
+		for i, id := range pn.Allowlist {
+			allowlist[i], err = peer.Decode(id)
+			if err != nil {
+				logger.Fatal("invalid peer ID in private network allowlist", zap.String("peer", id), zap.Error(err))
+			}
 		}
+		hostCfg.Gate = p2p.NewGate(cfg.P2P.Network, allowlist, pn.GuardianAuth, gst)
+
+		if pn.PSKFile != "" {
+			hostCfg.PSK, err = loadPSK(pn.PSKFile)
+			if err != nil {
+				logger.Fatal("failed to load private network PSK", zap.String("path", pn.PSKFile), zap.Error(err))
+			}
+		}
+
+		logger.Info("running in private network mode",
+			zap.Int("allowlisted_peers", len(allowlist)),
+			zap.Bool("guardian_auth", pn.GuardianAuth),
+			zap.Bool("psk", hostCfg.PSK != nil))
+	}
+
+	// The libp2p host and gossip topic are long-lived and shared by the (restartable) p2p runnables.
+	h, err := p2p.NewHost(rootCtx, priv, hostCfg)
+	if err != nil {
+		logger.Fatal("failed to create p2p host", zap.Error(err))
+	}
+	defer h.Close()
+
+	if hostCfg.Gate != nil {
+		if err := hostCfg.Gate.Attach(rootCtx, h, gk, logger.Named("p2pgate")); err != nil {
+			logger.Fatal("failed to start peer authentication", zap.Error(err))
+		}
+	}
 
-		if err := supervisor.Run(ctx, "ethwatch",
-			ethereum.NewEthBridgeWatcher(*ethRPC, ethContractAddr, *ethConfirmations, lockC, setC).Run); err != nil {
+	p2pNode, err := p2p.New(rootCtx, h, cfg.P2P.Network, cfg.P2P.LegacyBroadcast, hostCfg.Gate, obsvC, sendC, rawHeartbeatListeners, gk, gst,
+		cfg.P2P.Bootstrap, cfg.P2P.Peerstore, cfg.P2P.MinPeers, cfg.NodeName)
+	if err != nil {
+		logger.Fatal("failed to start p2p node", zap.Error(err))
+	}
+
+	// Run supervisor.
+	sup := supervisor.New(rootCtx, logger, func(ctx context.Context) error {
+		if err := supervisor.Run(ctx, "p2p", p2pNode.Run); err != nil {
 			return err
 		}
 
-		// Start Terra watcher only if configured
-		if *terraSupport {
-			logger.Info("Starting Terra watcher")
-			if err := supervisor.Run(ctx, "terrawatch",
-				terra.NewTerraBridgeWatcher(*terraWS, *terraLCD, *terraContract, lockC, setC).Run); err != nil {
+		for _, c := range chains.All() {
+			stats := p2p.DefaultRegistry.ChainStats(c.ID())
+			watcher := c.Watcher(lockC, setC, stats)
+			bw, batch := c.(common.BatchWatcher)
+			batch = batch && cfg.BatchTransfers
+			if batch {
+				watcher = bw.BatchWatcher(lockBatchC, setC, stats)
+			}
+
+			logger.Info("Starting watcher", zap.Stringer("chain", c.ID()), zap.Bool("batch", batch))
+			if err := supervisor.Run(ctx, c.Name()+"watch", watcher); err != nil {
 				return err
 			}
 		}
 
-		if *qtumSupport {
-			logger.Info("Starting Qtum watcher")
-			if err := supervisor.Run(ctx, "qtumwatch",
-				qtum.NewQtumBridgeWatcher(*qtumRPC, *qtumContract, *qtumChainID, *qtumConfirmations, lockC, setC).Run); err != nil {
+		if cfg.Fake.Enabled {
+			// Validated by cfg.Validate.
+			routes, _ := fake.ParseRoutes(cfg.Fake.Routes)
+			g, err := fake.NewGenerator(cfg.Fake.Seed, routes)
+			if err != nil {
+				return err
+			}
+			logger.Warn("Starting fake watcher emitting synthetic events", zap.Float64("rate", cfg.Fake.Rate))
+			if err := supervisor.Run(ctx, "fakewatch", fake.NewWatcher(g, cfg.Fake.Rate, lockC, msgC).Run); err != nil {
 				return err
 			}
 		}
 
 		if err := supervisor.Run(ctx, "solvaa",
-			solana.NewSolanaVAASubmitter(*agentRPC, solanaVaaC, false).Run); err != nil {
-			return err
-		}
-
-		if err := supervisor.Run(ctx, "solwatch",
-			solana.NewSolanaWatcher(*solanaWsRPC, *solanaRPC, solBridgeAddress, lockC).Run); err != nil {
+			solana.NewSolanaVAASubmitter(cfg.Solana.AgentRPC, solanaVaaC, false).Run); err != nil {
 			return err
 		}
 
 		// TODO: this thing has way too many arguments at this point - make it an options struct
 		p := processor.NewProcessor(ctx,
 			lockC,
+			lockBatchC,
+			msgC,
 			setC,
 			sendC,
 			obsvC,
 			solanaVaaC,
+			nil,
 			injectC,
+			timelineC,
 			gk,
-			*unsafeDevMode,
-			*devNumGuardians,
-			*ethRPC,
-			*terraSupport,
-			*terraLCD,
-			*terraChainID,
-			*terraContract,
-			terraFeePayer,
-			*qtumSupport,
-			*qtumRPC,
-			*qtumChainID,
-			*qtumContract,
-			qtumFeePayer,
+			chains,
+			gst,
+			cfg.UnsafeDevMode,
+			cfg.DevNumGuardians,
+			cfg.PauseState,
 		)
 		if err := supervisor.Run(ctx, "processor", p.Run); err != nil {
 			return err
@@ -508,15 +468,9 @@ func runBridge(cmd *cobra.Command, args []string) {
 		if err := supervisor.Run(ctx, "admin", adminService); err != nil {
 			return err
 		}
-		if *publicRPC != "" {
-			if err := supervisor.Run(ctx, "publicrpc",
-				publicrpc.PublicrpcServiceRunnable(logger, *publicRPC, rawHeartbeatListeners)); err != nil {
-				return err
-			}
-		}
-		if *publicRPC != "" {
+		if cfg.PublicRPC.Addr != "" {
 			if err := supervisor.Run(ctx, "publicrpc",
-				publicrpc.PublicrpcServiceRunnable(logger, *publicRPC, rawHeartbeatListeners)); err != nil {
+				publicrpc.PublicrpcServiceRunnable(logger, cfg.PublicRPC.Addr, rawHeartbeatListeners)); err != nil {
 				return err
 			}
 		}
@@ -532,6 +486,46 @@ func runBridge(cmd *cobra.Command, args []string) {
 		// rather than attempting to reschedule the runnable.
 		supervisor.WithPropagatePanic)
 
+	if cfg.Status.Addr != "" {
+		// Use a custom routing instead of using http.DefaultServeMux directly to avoid accidentally exposing packages
+		// that register themselves with it by default (like pprof).
+		router := mux.NewRouter()
+
+		// pprof server. NOT necessarily safe to expose publicly - only enable it in dev mode to avoid exposing it by
+		// accident. There's benefit to having pprof enabled on production nodes, but we would likely want to expose it
+		// via a dedicated port listening on localhost, or via the admin UNIX socket.
+		if cfg.UnsafeDevMode {
+			// Pass requests to http.DefaultServeMux, which pprof automatically registers with as an import side-effect.
+			router.PathPrefix("/debug/pprof/").Handler(http.DefaultServeMux)
+		}
+
+		// Simple endpoint exposing node readiness (safe to expose to untrusted clients)
+		router.HandleFunc("/readyz", readiness.Handler)
+
+		// Liveness endpoint reporting stale components (safe to expose to untrusted clients)
+		router.HandleFunc("/healthz", readiness.LivenessHandler(cfg.Status.StaleThreshold))
+
+		// Prometheus metrics (safe to expose to untrusted clients)
+		router.Handle("/metrics", promhttp.Handler())
+
+		// Supervision tree status. NOT safe to expose publicly - runnable errors may include sensitive details like
+		// RPC URLs with credentials. Like pprof, it's only enabled in dev mode. In production, the same information
+		// is available via the admin socket.
+		if cfg.UnsafeDevMode {
+			router.HandleFunc("/debug/supervisor", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				e := json.NewEncoder(w)
+				e.SetIndent("", "  ")
+				_ = e.Encode(sup.Snapshot())
+			})
+		}
+
+		go func() {
+			logger.Info("status server listening", zap.String("addr", cfg.Status.Addr))
+			logger.Error("status server crashed", zap.Error(http.ListenAndServe(cfg.Status.Addr, router)))
+		}()
+	}
+
 	select {
 	case <-rootCtx.Done():
 		logger.Info("root context cancelled, exiting...")
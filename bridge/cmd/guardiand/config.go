@@ -0,0 +1,505 @@
+package guardiand
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	solana_types "github.com/dfuse-io/solana-go"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	ipfslog "github.com/ipfs/go-log/v2"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/certusone/wormhole/bridge/pkg/devnet"
+	"github.com/certusone/wormhole/bridge/pkg/fake"
+	"github.com/certusone/wormhole/bridge/pkg/p2p"
+)
+
+// Config is the guardiand bridge configuration. It can be loaded from a YAML or TOML file (see the --config flag),
+// and every value can be overridden by environment variables (GUARDIAND_<SECTION>_<KEY>) and command line flags,
+// in increasing order of precedence.
+type Config struct {
+	// Node name to announce in gossip heartbeats.
+	NodeName string `mapstructure:"nodeName"`
+	// Path to guardian key.
+	GuardianKey string `mapstructure:"guardianKey"`
+	// Logging level (debug, info, warn, error, dpanic, panic, fatal).
+	LogLevel string `mapstructure:"logLevel"`
+
+	// Launch node in unsafe, deterministic devnet mode.
+	UnsafeDevMode bool `mapstructure:"unsafeDevMode"`
+	// Number of devnet guardians to include in guardian set.
+	DevNumGuardians uint `mapstructure:"devNumGuardians"`
+
+	// Additionally sign the lockups confirmed in the same block as a single batch VAA, on chains that support it.
+	// All guardians need to agree on this setting, otherwise batches don't reach quorum.
+	BatchTransfers bool `mapstructure:"batchTransfers"`
+
+	// Path to the persisted bridge pause state. Only optional in devnet mode, where a restarted node signs lockups
+	// involving paused chains until the pause VAA is injected again.
+	PauseState string `mapstructure:"pauseState"`
+
+	P2P       P2PConfig       `mapstructure:"p2p"`
+	Admin     AdminConfig     `mapstructure:"admin"`
+	Status    StatusConfig    `mapstructure:"status"`
+	PublicRPC PublicRPCConfig `mapstructure:"publicrpc"`
+
+	Ethereum EthereumConfig `mapstructure:"ethereum"`
+	Solana   SolanaConfig   `mapstructure:"solana"`
+	Terra    TerraConfig    `mapstructure:"terra"`
+	Qtum     QtumConfig     `mapstructure:"qtum"`
+
+	Fake FakeConfig `mapstructure:"fake"`
+}
+
+type P2PConfig struct {
+	// P2P network identifier.
+	Network string `mapstructure:"network"`
+	// P2P listener port, used by the default listen addresses.
+	Port uint `mapstructure:"port"`
+	// Enabled P2P transports (quic, tcp). Defaults to quic, or tcp in private networks using a PSK.
+	Transports []string `mapstructure:"transports"`
+	// Security protocols for the TCP transport (tls, noise), in order of preference. Defaults to tls.
+	Security []string `mapstructure:"security"`
+	// Multiaddrs to listen on. Defaults to all interfaces on the P2P port, for each enabled transport.
+	ListenAddrs []string `mapstructure:"listenAddrs"`
+	// Multiaddrs to advertise to peers instead of the listen addresses, e.g. the public address of a NAT gateway.
+	AnnounceAddrs []string `mapstructure:"announceAddrs"`
+	// Connection manager limits.
+	ConnMgr ConnMgrConfig `mapstructure:"connMgr"`
+	// P2P bootstrap peers (comma-separated).
+	Bootstrap string `mapstructure:"bootstrap"`
+	// Path to the persistent peerstore, whose peers are used as additional bootstrap candidates (disabled if blank).
+	Peerstore string `mapstructure:"peerstore"`
+	// Minimum number of connected guardian peers. Bootstrap and known peers are re-dialed while below this number.
+	MinPeers int `mapstructure:"minPeers"`
+	// Whether to keep using the pre-versioning broadcast topic alongside the per-message-type topics,
+	// for compatibility with nodes running outdated software.
+	LegacyBroadcast bool `mapstructure:"legacyBroadcast"`
+	// Path to node key (will be generated if it doesn't exist).
+	NodeKey string `mapstructure:"nodeKey"`
+	// Private network mode settings, per network ID. Only the entry matching Network applies (config file only).
+	PrivateNetworks []PrivateNetworkConfig `mapstructure:"privateNetworks"`
+}
+
+type ConnMgrConfig struct {
+	// Number of connections the connection manager trims down to.
+	LowWater int `mapstructure:"lowWater"`
+	// Number of connections above which the connection manager starts trimming connections.
+	HighWater int `mapstructure:"highWater"`
+	// Minimum age of connections before they can be trimmed.
+	GracePeriod time.Duration `mapstructure:"gracePeriod"`
+}
+
+type PrivateNetworkConfig struct {
+	// P2P network identifier this entry applies to.
+	Network string `mapstructure:"network"`
+	// Peer IDs admitted without authentication.
+	Allowlist []string `mapstructure:"allowlist"`
+	// Whether to admit peers which prove control of a key in the current guardian set.
+	GuardianAuth bool `mapstructure:"guardianAuth"`
+	// Path to a libp2p private network pre-shared key (optional). Switches the P2P transport from QUIC to TCP.
+	PSKFile string `mapstructure:"pskFile"`
+}
+
+// PrivateNetwork returns the private network mode settings for the configured network ID,
+// or nil if the network is public.
+func (c *P2PConfig) PrivateNetwork() *PrivateNetworkConfig {
+	for i := range c.PrivateNetworks {
+		if c.PrivateNetworks[i].Network == c.Network {
+			return &c.PrivateNetworks[i]
+		}
+	}
+	return nil
+}
+
+type AdminConfig struct {
+	// Admin gRPC service UNIX domain socket path.
+	Socket string `mapstructure:"socket"`
+}
+
+type StatusConfig struct {
+	// Listen address for status server (disabled if blank).
+	Addr string `mapstructure:"addr"`
+	// Maximum time without progress before a component is reported as unhealthy by /healthz.
+	StaleThreshold time.Duration `mapstructure:"staleThreshold"`
+}
+
+type PublicRPCConfig struct {
+	// Listen address for public gRPC interface (disabled if blank).
+	Addr string `mapstructure:"addr"`
+}
+
+type EthereumConfig struct {
+	// Ethereum is the guardian set source of truth and cannot be disabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Ethereum RPC URL.
+	RPC string `mapstructure:"rpc"`
+	// Ethereum bridge contract address.
+	Contract string `mapstructure:"contract"`
+	// Ethereum confirmation count requirement.
+	Confirmations uint64 `mapstructure:"confirmations"`
+}
+
+type SolanaConfig struct {
+	// Solana stores every VAA for data availability and cannot be disabled.
+	Enabled bool `mapstructure:"enabled"`
+	// Solana RPC URL.
+	RPC string `mapstructure:"rpc"`
+	// Solana Websocket URL.
+	WS string `mapstructure:"ws"`
+	// Address of the Solana Bridge Program.
+	Contract string `mapstructure:"contract"`
+	// Solana agent sidecar gRPC socket path.
+	AgentRPC string `mapstructure:"agentRPC"`
+}
+
+type TerraConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Path to terrad root for websocket connection.
+	WS string `mapstructure:"ws"`
+	// Path to LCD service root for http calls.
+	LCD string `mapstructure:"lcd"`
+	// Terra chain ID, used in LCD client initialization.
+	ChainID string `mapstructure:"chainID"`
+	// Wormhole contract address on Terra blockchain.
+	Contract string `mapstructure:"contract"`
+	// Path to mnemonic for account paying gas for submitting transactions to Terra.
+	Key string `mapstructure:"key"`
+}
+
+type QtumConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Qtum RPC URL.
+	RPC string `mapstructure:"rpc"`
+	// Qtum bridge contract address.
+	Contract string `mapstructure:"contract"`
+	// Qtum chain ID, used in client.
+	ChainID string `mapstructure:"chainID"`
+	// Qtum confirmation count requirement.
+	Confirmations uint64 `mapstructure:"confirmations"`
+	// Path to wif for account paying gas for submitting transactions to Qtum.
+	Key string `mapstructure:"key"`
+}
+
+type FakeConfig struct {
+	// Emit synthetic lockups for load testing. Requires unsafeDevMode, since they are signed like real ones.
+	Enabled bool `mapstructure:"enabled"`
+	// Synthetic lockups per second, across all routes.
+	Rate float64 `mapstructure:"rate"`
+	// Source and target chains of synthetic lockups, as <source>:<target>[=<weight>], or <source>:message[=<weight>]
+	// for synthetic message publications.
+	// Defaults to ethereum:solana and solana:ethereum in equal parts.
+	Routes []string `mapstructure:"routes"`
+	// Seed of the synthetic lockups. Guardians need to use the same seed to reach quorum.
+	Seed int64 `mapstructure:"seed"`
+}
+
+// bridgeFlagKeys maps BridgeCmd command line flags to their config keys. The flag names predate the config file
+// and are kept for compatibility with existing deployments.
+var bridgeFlagKeys = map[string]string{
+	"network":   "p2p.network",
+	"port":      "p2p.port",
+	"bootstrap": "p2p.bootstrap",
+	"peerstore": "p2p.peerstore",
+	"minPeers":  "p2p.minPeers",
+	"nodeKey":   "p2p.nodeKey",
+
+	"p2pTransports":      "p2p.transports",
+	"p2pSecurity":        "p2p.security",
+	"p2pListenAddrs":     "p2p.listenAddrs",
+	"p2pAnnounceAddrs":   "p2p.announceAddrs",
+	"connMgrLowWater":    "p2p.connMgr.lowWater",
+	"connMgrHighWater":   "p2p.connMgr.highWater",
+	"connMgrGracePeriod": "p2p.connMgr.gracePeriod",
+
+	"legacyBroadcast": "p2p.legacyBroadcast",
+
+	"adminSocket": "admin.socket",
+	"statusAddr":  "status.addr",
+
+	"statusStaleThreshold": "status.staleThreshold",
+	"publicRPC":            "publicrpc.addr",
+
+	"bridgeKey":       "guardianKey",
+	"nodeName":        "nodeName",
+	"logLevel":        "logLevel",
+	"unsafeDevMode":   "unsafeDevMode",
+	"devNumGuardians": "devNumGuardians",
+	"batchTransfers":  "batchTransfers",
+	"pauseState":      "pauseState",
+
+	"ethRPC":           "ethereum.rpc",
+	"ethContract":      "ethereum.contract",
+	"ethConfirmations": "ethereum.confirmations",
+
+	"solanaRPC":           "solana.rpc",
+	"solanaWS":            "solana.ws",
+	"solanaBridgeAddress": "solana.contract",
+	"agentRPC":            "solana.agentRPC",
+
+	"terra":         "terra.enabled",
+	"terraWS":       "terra.ws",
+	"terraLCD":      "terra.lcd",
+	"terraChainID":  "terra.chainID",
+	"terraContract": "terra.contract",
+	"terraKey":      "terra.key",
+
+	"qtum":              "qtum.enabled",
+	"qtumRPC":           "qtum.rpc",
+	"qtumContract":      "qtum.contract",
+	"qtumChainID":       "qtum.chainID",
+	"qtumConfirmations": "qtum.confirmations",
+	"qtumKey":           "qtum.key",
+
+	"fake":       "fake.enabled",
+	"fakeRate":   "fake.rate",
+	"fakeRoutes": "fake.routes",
+	"fakeSeed":   "fake.seed",
+}
+
+// bindBridgeConfig binds the bridge command line flags and environment variables to v
+// and sets defaults for keys that have no flag.
+func bindBridgeConfig(v *viper.Viper, flags *pflag.FlagSet) error {
+	for name, key := range bridgeFlagKeys {
+		f := flags.Lookup(name)
+		if f == nil {
+			panic(fmt.Sprintf("flag %s is not defined", name))
+		}
+		if err := v.BindPFlag(key, f); err != nil {
+			return fmt.Errorf("failed to bind flag %s: %w", name, err)
+		}
+	}
+
+	v.SetDefault("ethereum.enabled", true)
+	v.SetDefault("solana.enabled", true)
+
+	v.SetEnvPrefix("guardiand")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	return nil
+}
+
+// loadBridgeConfig merges config file, environment and flags into a Config.
+// The returned config is not validated.
+func loadBridgeConfig(v *viper.Viper, flags *pflag.FlagSet) (*Config, error) {
+	if err := bindBridgeConfig(v, flags); err != nil {
+		return nil, err
+	}
+
+	var c Config
+	if err := v.Unmarshal(&c); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	return &c, nil
+}
+
+// applyDevModeDefaults fills in a number of unset values that rely on deterministic devnet keys. Values set by the
+// config file, environment or flags are left as is. It's a no-op unless UnsafeDevMode is set.
+func (c *Config) applyDevModeDefaults() error {
+	if !c.UnsafeDevMode {
+		return nil
+	}
+
+	if c.P2P.Bootstrap == "" {
+		g0key, err := peer.IDFromPrivateKey(devnet.DeterministicP2PPrivKeyByIndex(0))
+		if err != nil {
+			return err
+		}
+
+		// Use the first guardian node as bootstrap
+		c.P2P.Bootstrap = fmt.Sprintf("/dns4/guardian-0.guardian/udp/%d/quic/p2p/%s", c.P2P.Port, g0key.String())
+	}
+
+	if c.Ethereum.Contract == "" {
+		// Deterministic ganache ETH devnet address.
+		c.Ethereum.Contract = devnet.GanacheBridgeContractAddress.Hex()
+	}
+
+	if c.NodeName == "" {
+		// Use the hostname as nodeName. For production, we don't want to do this to
+		// prevent accidentally leaking sensitive hostnames.
+		hostname, err := os.Hostname()
+		if err != nil {
+			return err
+		}
+		c.NodeName = hostname
+	}
+
+	return nil
+}
+
+// ConfigError lists every problem found while validating a Config.
+type ConfigError []string
+
+func (e ConfigError) Error() string {
+	return fmt.Sprintf("invalid config:\n  %s", strings.Join(e, "\n  "))
+}
+
+// Validate checks the config for missing or inconsistent values. Unlike a chain of fatal log calls,
+// it reports all problems at once. The error is of type ConfigError.
+func (c *Config) Validate() error {
+	var errs ConfigError
+
+	require := func(key string, value string) {
+		if value == "" {
+			errs = append(errs, fmt.Sprintf("%s is required%s", key, flagHint(key)))
+		}
+	}
+
+	if !c.UnsafeDevMode { // In devnet mode, keys are deterministically generated.
+		require("p2p.nodeKey", c.P2P.NodeKey)
+	}
+	if !c.UnsafeDevMode { // Production nodes must not forget a pause when they restart.
+		require("pauseState", c.PauseState)
+	}
+	if _, err := ipfslog.LevelFromString(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Sprintf("logLevel %q is invalid%s", c.LogLevel, flagHint("logLevel")))
+	}
+	require("guardianKey", c.GuardianKey)
+	require("admin.socket", c.Admin.Socket)
+	require("nodeName", c.NodeName)
+	require("p2p.network", c.P2P.Network)
+	if c.P2P.Port == 0 || c.P2P.Port > 65535 {
+		errs = append(errs, fmt.Sprintf("p2p.port must be between 1 and 65535, got %d", c.P2P.Port))
+	}
+	if c.P2P.MinPeers < 0 {
+		errs = append(errs, fmt.Sprintf("p2p.minPeers cannot be negative, got %d", c.P2P.MinPeers))
+	}
+
+	for _, t := range c.P2P.Transports {
+		if !p2p.IsValidTransport(t) {
+			errs = append(errs, fmt.Sprintf("p2p.transports: unsupported transport %q (expected %s or %s)",
+				t, p2p.TransportQUIC, p2p.TransportTCP))
+		}
+	}
+	for _, sec := range c.P2P.Security {
+		if !p2p.IsValidSecurity(sec) {
+			errs = append(errs, fmt.Sprintf("p2p.security: unsupported security protocol %q (expected %s or %s)",
+				sec, p2p.SecurityTLS, p2p.SecurityNoise))
+		}
+	}
+	requireMultiaddrs := func(key string, addrs []string) {
+		for _, a := range addrs {
+			if _, err := multiaddr.NewMultiaddr(a); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: invalid multiaddr %q: %v", key, a, err))
+			}
+		}
+	}
+	requireMultiaddrs("p2p.listenAddrs", c.P2P.ListenAddrs)
+	requireMultiaddrs("p2p.announceAddrs", c.P2P.AnnounceAddrs)
+	if c.P2P.ConnMgr.LowWater < 0 || c.P2P.ConnMgr.HighWater < c.P2P.ConnMgr.LowWater {
+		errs = append(errs, fmt.Sprintf("p2p.connMgr: expected 0 <= lowWater <= highWater, got lowWater %d, highWater %d",
+			c.P2P.ConnMgr.LowWater, c.P2P.ConnMgr.HighWater))
+	}
+	if c.P2P.ConnMgr.GracePeriod < 0 {
+		errs = append(errs, fmt.Sprintf("p2p.connMgr.gracePeriod cannot be negative, got %s", c.P2P.ConnMgr.GracePeriod))
+	}
+
+	seenNetworks := map[string]bool{}
+	for i, pn := range c.P2P.PrivateNetworks {
+		key := fmt.Sprintf("p2p.privateNetworks[%d]", i)
+		require(key+".network", pn.Network)
+		if seenNetworks[pn.Network] {
+			errs = append(errs, fmt.Sprintf("%s: duplicate entry for network %q", key, pn.Network))
+		}
+		seenNetworks[pn.Network] = true
+
+		if len(pn.Allowlist) == 0 && !pn.GuardianAuth {
+			errs = append(errs, fmt.Sprintf("%s: either allowlist or guardianAuth is required, otherwise no peer is admitted", key))
+		}
+		if pn.PSKFile != "" && pn.Network == c.P2P.Network {
+			for _, t := range c.P2P.Transports {
+				if t == p2p.TransportQUIC {
+					errs = append(errs, fmt.Sprintf("%s.pskFile: the quic transport does not support private networks, use tcp", key))
+				}
+			}
+		}
+		for _, id := range pn.Allowlist {
+			if _, err := peer.Decode(id); err != nil {
+				errs = append(errs, fmt.Sprintf("%s.allowlist: invalid peer ID %q: %v", key, id, err))
+			}
+		}
+	}
+
+	if c.Status.StaleThreshold <= 0 {
+		errs = append(errs, fmt.Sprintf("status.staleThreshold must be positive, got %s%s",
+			c.Status.StaleThreshold, flagHint("status.staleThreshold")))
+	}
+
+	if !c.Ethereum.Enabled {
+		errs = append(errs, "ethereum.enabled cannot be false - Ethereum is the guardian set source of truth")
+	}
+	require("ethereum.rpc", c.Ethereum.RPC)
+	require("ethereum.contract", c.Ethereum.Contract)
+	if c.Ethereum.Contract != "" && !eth_common.IsHexAddress(c.Ethereum.Contract) {
+		errs = append(errs, fmt.Sprintf("ethereum.contract %q is not a valid address", c.Ethereum.Contract))
+	}
+
+	if !c.Solana.Enabled {
+		errs = append(errs, "solana.enabled cannot be false - every VAA is stored on Solana for data availability")
+	}
+	require("solana.rpc", c.Solana.RPC)
+	require("solana.ws", c.Solana.WS)
+	require("solana.contract", c.Solana.Contract)
+	if c.Solana.Contract != "" {
+		if _, err := solana_types.PublicKeyFromBase58(c.Solana.Contract); err != nil {
+			errs = append(errs, fmt.Sprintf("solana.contract %q is not a valid address: %v", c.Solana.Contract, err))
+		}
+	}
+	require("solana.agentRPC", c.Solana.AgentRPC)
+
+	if c.Terra.Enabled {
+		require("terra.ws", c.Terra.WS)
+		require("terra.lcd", c.Terra.LCD)
+		require("terra.chainID", c.Terra.ChainID)
+		require("terra.contract", c.Terra.Contract)
+		require("terra.key", c.Terra.Key)
+	}
+
+	if c.Qtum.Enabled {
+		require("qtum.rpc", c.Qtum.RPC)
+		require("qtum.contract", c.Qtum.Contract)
+		require("qtum.chainID", c.Qtum.ChainID)
+		require("qtum.key", c.Qtum.Key)
+	}
+
+	if c.Fake.Enabled {
+		if !c.UnsafeDevMode {
+			errs = append(errs, "fake.enabled requires unsafeDevMode - synthetic lockups are signed like real ones")
+		}
+		if c.Fake.Rate <= 0 {
+			errs = append(errs, fmt.Sprintf("fake.rate must be positive, got %g%s", c.Fake.Rate, flagHint("fake.rate")))
+		}
+		if _, err := fake.ParseRoutes(c.Fake.Routes); err != nil {
+			errs = append(errs, fmt.Sprintf("fake.routes: %v", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// flagHint returns a " (--flag)" suffix naming the command line flag for a given config key, if any.
+func flagHint(key string) string {
+	var names []string
+	for name, k := range bridgeFlagKeys {
+		if k == key {
+			names = append(names, "--"+name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return fmt.Sprintf(" (%s)", strings.Join(names, ", "))
+}
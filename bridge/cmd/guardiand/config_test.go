@@ -0,0 +1,199 @@
+package guardiand
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certusone/wormhole/bridge/pkg/devnet"
+)
+
+// validConfig returns a minimal config which passes validation.
+func validConfig() *Config {
+	return &Config{
+		NodeName:    "guardian",
+		GuardianKey: "/run/guardiand/guardian.key",
+		LogLevel:    "info",
+		P2P: P2PConfig{
+			Network: "/wormhole/dev",
+			Port:    8999,
+			NodeKey: "/run/guardiand/node.key",
+		},
+		Admin:      AdminConfig{Socket: "/run/guardiand/admin.socket"},
+		Status:     StatusConfig{StaleThreshold: time.Minute},
+		PauseState: "/var/lib/guardiand/pause.json",
+		Ethereum: EthereumConfig{
+			Enabled:  true,
+			RPC:      "ws://eth-devnet:8545",
+			Contract: devnet.GanacheBridgeContractAddress.Hex(),
+		},
+		Solana: SolanaConfig{
+			Enabled:  true,
+			RPC:      "http://solana-devnet:8899",
+			WS:       "ws://solana-devnet:8900",
+			Contract: devnet.SolanaBridgeContract,
+			AgentRPC: "/run/agent.socket",
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		// Expected problems, or none if the config is valid.
+		errs []string
+	}{
+		{name: "valid", modify: func(c *Config) {}},
+		{name: "devnet without node key", modify: func(c *Config) {
+			c.UnsafeDevMode = true
+			c.P2P.NodeKey = ""
+		}},
+		{name: "missing node key", modify: func(c *Config) { c.P2P.NodeKey = "" },
+			errs: []string{"p2p.nodeKey is required (--nodeKey)"}},
+		{name: "devnet without pause state", modify: func(c *Config) {
+			c.UnsafeDevMode = true
+			c.PauseState = ""
+		}},
+		{name: "missing pause state", modify: func(c *Config) { c.PauseState = "" },
+			errs: []string{"pauseState is required (--pauseState)"}},
+		{name: "invalid log level", modify: func(c *Config) { c.LogLevel = "loud" },
+			errs: []string{`logLevel "loud" is invalid (--logLevel)`}},
+		{name: "invalid port", modify: func(c *Config) { c.P2P.Port = 70000 },
+			errs: []string{"p2p.port must be between 1 and 65535, got 70000"}},
+		{name: "invalid transport", modify: func(c *Config) { c.P2P.Transports = []string{"udp"} },
+			errs: []string{`p2p.transports: unsupported transport "udp"`}},
+		{name: "invalid connection manager limits", modify: func(c *Config) {
+			c.P2P.ConnMgr.LowWater = 10
+			c.P2P.ConnMgr.HighWater = 5
+		}, errs: []string{"p2p.connMgr: expected 0 <= lowWater <= highWater"}},
+		{name: "private network without admission", modify: func(c *Config) {
+			c.P2P.PrivateNetworks = []PrivateNetworkConfig{{Network: "/wormhole/dev"}}
+		}, errs: []string{"p2p.privateNetworks[0]: either allowlist or guardianAuth is required"}},
+		{name: "ethereum disabled", modify: func(c *Config) { c.Ethereum.Enabled = false },
+			errs: []string{"ethereum.enabled cannot be false"}},
+		{name: "invalid ethereum contract", modify: func(c *Config) { c.Ethereum.Contract = "0x1234" },
+			errs: []string{`ethereum.contract "0x1234" is not a valid address`}},
+		{name: "invalid solana contract", modify: func(c *Config) { c.Solana.Contract = "0x1234" },
+			errs: []string{`solana.contract "0x1234" is not a valid address`}},
+		{name: "terra enabled without settings", modify: func(c *Config) { c.Terra.Enabled = true },
+			errs: []string{
+				"terra.ws is required (--terraWS)",
+				"terra.lcd is required (--terraLCD)",
+				"terra.chainID is required (--terraChainID)",
+				"terra.contract is required (--terraContract)",
+				"terra.key is required (--terraKey)",
+			}},
+		{name: "fake lockups outside of devnet", modify: func(c *Config) {
+			c.Fake.Enabled = true
+			c.Fake.Rate = 1
+		}, errs: []string{"fake.enabled requires unsafeDevMode"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.modify(c)
+
+			err := c.Validate()
+			if len(tt.errs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var cerr ConfigError
+			require.True(t, errors.As(err, &cerr), "expected ConfigError, got %T", err)
+			require.Len(t, cerr, len(tt.errs), "%v", cerr)
+			for i, e := range tt.errs {
+				assert.Contains(t, cerr[i], e)
+			}
+		})
+	}
+}
+
+func TestConfigErrorListsAllProblems(t *testing.T) {
+	err := (&Config{}).Validate()
+
+	var cerr ConfigError
+	require.True(t, errors.As(err, &cerr))
+	assert.Greater(t, len(cerr), 1)
+	assert.Contains(t, err.Error(), "invalid config:\n  ")
+	for _, e := range cerr {
+		assert.Contains(t, err.Error(), e)
+	}
+}
+
+func TestLoadBridgeConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guardiand.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+nodeName: file
+p2p:
+  network: file
+  bootstrap: file
+ethereum:
+  rpc: file
+`), 0600))
+
+	// Flags beat the environment, which beats the config file.
+	for k, v := range map[string]string{
+		"GUARDIAND_NODENAME":    "env",
+		"GUARDIAND_P2P_NETWORK": "env",
+	} {
+		require.NoError(t, os.Setenv(k, v))
+		defer os.Unsetenv(k)
+	}
+
+	flags := pflag.NewFlagSet("bridge", pflag.ContinueOnError)
+	addBridgeFlags(flags)
+	require.NoError(t, flags.Parse([]string{"--network", "flag"}))
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+
+	c, err := loadBridgeConfig(v, flags)
+	require.NoError(t, err)
+
+	assert.Equal(t, "flag", c.P2P.Network)
+	assert.Equal(t, "env", c.NodeName)
+	assert.Equal(t, "file", c.P2P.Bootstrap)
+	assert.Equal(t, "file", c.Ethereum.RPC)
+
+	// Unset values fall back to the flag defaults.
+	assert.Equal(t, uint(8999), c.P2P.Port)
+	assert.Equal(t, "info", c.LogLevel)
+	assert.True(t, c.Ethereum.Enabled)
+}
+
+func TestApplyDevModeDefaults(t *testing.T) {
+	c := validConfig()
+	c.NodeName = ""
+	c.Ethereum.Contract = ""
+	require.NoError(t, c.applyDevModeDefaults())
+	assert.Empty(t, c.NodeName, "no-op outside of devnet mode")
+	assert.Empty(t, c.Ethereum.Contract, "no-op outside of devnet mode")
+
+	c.UnsafeDevMode = true
+	require.NoError(t, c.applyDevModeDefaults())
+	assert.NotEmpty(t, c.NodeName)
+	assert.NotEmpty(t, c.P2P.Bootstrap)
+	assert.Equal(t, devnet.GanacheBridgeContractAddress.Hex(), c.Ethereum.Contract)
+
+	// Explicitly configured values are kept.
+	c = validConfig()
+	c.UnsafeDevMode = true
+	c.P2P.Bootstrap = "/dns4/bootstrap/udp/8999/quic/p2p/12D3KooWL3XJ9EMCyZvmmGXL2LMiVBtrVa2BuESsJiXkSj7333Jw"
+	c.Ethereum.Contract = "0x0000000000000000000000000000000000000001"
+	require.NoError(t, c.applyDevModeDefaults())
+	assert.Equal(t, "guardian", c.NodeName)
+	assert.Equal(t, "/dns4/bootstrap/udp/8999/quic/p2p/12D3KooWL3XJ9EMCyZvmmGXL2LMiVBtrVa2BuESsJiXkSj7333Jw", c.P2P.Bootstrap)
+	assert.Equal(t, "0x0000000000000000000000000000000000000001", c.Ethereum.Contract)
+}
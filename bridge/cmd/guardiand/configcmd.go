@@ -0,0 +1,56 @@
+package guardiand
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	ConfigCmd.AddCommand(ConfigValidateCmd)
+}
+
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Guardian node config file commands",
+}
+
+var ConfigValidateCmd = &cobra.Command{
+	Use:   "validate [FILENAME]",
+	Short: "Check a bridge config file for errors (offline)",
+	Long: "Check a bridge config file for errors (offline).\n\n" +
+		"Environment variables (GUARDIAND_<SECTION>_<KEY>) are applied on top of the file, " +
+		"just like they would be when running the node.",
+	Run:  runConfigValidate,
+	Args: cobra.ExactArgs(1),
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) {
+	v := viper.New()
+	v.SetConfigFile(args[0])
+	if err := v.ReadInConfig(); err != nil {
+		fmt.Printf("failed to read config file: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Bind to the (unset) bridge flags to pick up their defaults.
+	cfg, err := loadBridgeConfig(v, BridgeCmd.Flags())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if err := cfg.applyDevModeDefaults(); err != nil {
+		fmt.Printf("failed to apply devnet defaults: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s: OK\n", args[0])
+}
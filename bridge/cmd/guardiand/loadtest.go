@@ -0,0 +1,192 @@
+package guardiand
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/fake"
+	"github.com/certusone/wormhole/bridge/pkg/harness"
+	"github.com/certusone/wormhole/bridge/pkg/supervisor"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+var (
+	loadtestGuardians *int
+	loadtestRate      *float64
+	loadtestDuration  *time.Duration
+	loadtestDrain     *time.Duration
+	loadtestRoutes    *[]string
+	loadtestSeed      *int64
+	loadtestLogLevel  *string
+)
+
+func init() {
+	loadtestGuardians = LoadtestCmd.Flags().Int("guardians", 5, "Number of guardian nodes")
+	loadtestRate = LoadtestCmd.Flags().Float64("rate", 10, "Synthetic lockups per second")
+	loadtestDuration = LoadtestCmd.Flags().Duration("duration", 30*time.Second, "How long to emit lockups for")
+	loadtestDrain = LoadtestCmd.Flags().Duration("drain", 30*time.Second, "How long to wait for outstanding VAAs once all lockups were emitted")
+	loadtestRoutes = LoadtestCmd.Flags().StringSlice("routes", nil, "Source and target chains of synthetic lockups as <source>:<target>[=<weight>], or <source>:message[=<weight>] for messages (default ethereum:solana,solana:ethereum)")
+	loadtestSeed = LoadtestCmd.Flags().Int64("seed", 0, "Seed of synthetic lockups")
+	loadtestLogLevel = LoadtestCmd.Flags().String("logLevel", "error", "Logging level of the guardian nodes (debug, info, warn, error)")
+}
+
+var LoadtestCmd = &cobra.Command{
+	Use:   "loadtest",
+	Short: "Measure lockup-to-quorum latency of an in-process guardian network under synthetic load",
+	Long: "Measure lockup-to-quorum latency of an in-process guardian network under synthetic load.\n\n" +
+		"Runs --guardians nodes in a single process, connected by an in-memory network, and makes all of them " +
+		"observe synthetic lockups at the given rate. Latency is the time from a lockup being due to a node " +
+		"reaching quorum on it, including any time the lockup spent queued because the nodes fell behind.",
+	Run:  runLoadtest,
+	Args: cobra.NoArgs,
+}
+
+// loadtestT runs a test harness outside of a test, failing by exiting the process.
+type loadtestT struct {
+	mu      sync.Mutex
+	cleanup []func()
+}
+
+func (t *loadtestT) Fatalf(format string, args ...interface{}) {
+	t.runCleanup()
+	log.Fatalf(format, args...)
+}
+
+func (t *loadtestT) Cleanup(f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cleanup = append(t.cleanup, f)
+}
+
+func (t *loadtestT) runCleanup() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := len(t.cleanup) - 1; i >= 0; i-- {
+		t.cleanup[i]()
+	}
+	t.cleanup = nil
+}
+
+func runLoadtest(cmd *cobra.Command, args []string) {
+	if *loadtestGuardians < 1 {
+		log.Fatalf("--guardians must be positive")
+	}
+	if *loadtestRate <= 0 {
+		log.Fatalf("--rate must be positive")
+	}
+	routes, err := fake.ParseRoutes(*loadtestRoutes)
+	if err != nil {
+		log.Fatalf("invalid --routes: %v", err)
+	}
+	g, err := fake.NewGenerator(*loadtestSeed, routes)
+	if err != nil {
+		log.Fatalf("invalid --routes: %v", err)
+	}
+
+	var lvl zap.AtomicLevel
+	if err := lvl.UnmarshalText([]byte(*loadtestLogLevel)); err != nil {
+		log.Fatalf("invalid --logLevel: %v", err)
+	}
+	logCfg := zap.NewDevelopmentConfig()
+	logCfg.Level = lvl
+	logger, err := logCfg.Build()
+	if err != nil {
+		log.Fatalf("failed to create logger: %v", err)
+	}
+
+	// Lockup timestamps are the time they were due at, see fake.Watcher.
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+	)
+	onVAA := func(n *harness.Node, v *vaa.VAA) {
+		d := time.Since(v.Timestamp)
+		mu.Lock()
+		latencies = append(latencies, d)
+		mu.Unlock()
+	}
+
+	t := &loadtestT{}
+	defer t.runCleanup()
+
+	var chains []vaa.ChainID
+	for _, r := range routes {
+		chains = append(chains, r.Source)
+		if !r.Message {
+			chains = append(chains, r.Target)
+		}
+	}
+
+	h := harness.New(t, *loadtestGuardians,
+		harness.WithLogger(logger), harness.WithVAAHandler(onVAA), harness.WithChains(chains...))
+	h.Start()
+	fmt.Printf("started %d guardians, emitting %.1f lockups/s for %s\n", *loadtestGuardians, *loadtestRate, *loadtestDuration)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *loadtestDuration)
+	defer cancel()
+
+	lockC := make(chan *common.ChainLock)
+	msgC := make(chan *common.MessagePublication)
+	supervisor.New(ctx, logger, fake.NewWatcher(g, *loadtestRate, lockC, msgC).Run)
+
+	var emitted int
+	for done := false; !done; {
+		select {
+		case k := <-lockC:
+			h.InjectLock(k)
+			emitted++
+		case m := <-msgC:
+			h.InjectMessage(m)
+			emitted++
+		case <-ctx.Done():
+			done = true
+		}
+	}
+
+	// Wait for the outstanding VAAs.
+	expected := emitted * *loadtestGuardians
+	deadline := time.Now().Add(*loadtestDrain)
+	for {
+		mu.Lock()
+		n := len(latencies)
+		mu.Unlock()
+		if n >= expected || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	// If the nodes can't keep up, fewer lockups are emitted than were due.
+	fmt.Printf("lockups:  %d of %d due (%.1f/s)\n", emitted, int(*loadtestRate*loadtestDuration.Seconds()),
+		float64(emitted)/loadtestDuration.Seconds())
+	fmt.Printf("VAAs:     %d of %d (%d guardians x %d lockups)\n",
+		len(latencies), expected, *loadtestGuardians, emitted)
+	if len(latencies) == 0 {
+		return
+	}
+	fmt.Printf("latency:  p50 %s, p90 %s, p99 %s, max %s\n",
+		percentile(latencies, 0.5), percentile(latencies, 0.9), percentile(latencies, 0.99),
+		percentile(latencies, 1))
+}
+
+// percentile returns the p-th percentile (0 < p <= 1) of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i].Round(time.Microsecond)
+}
@@ -7,6 +7,7 @@ import (
 
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/pnet"
 	"go.uber.org/zap"
 )
 
@@ -53,3 +54,19 @@ func getOrCreateNodeKey(logger *zap.Logger, path string) (p2pcrypto.PrivKey, err
 
 	return priv, nil
 }
+
+// loadPSK reads a libp2p private network pre-shared key in the standard (go-ipfs swarm.key) format.
+func loadPSK(path string) (pnet.PSK, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PSK: %w", err)
+	}
+	defer f.Close()
+
+	psk, err := pnet.DecodeV1PSK(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PSK: %w", err)
+	}
+
+	return psk, nil
+}
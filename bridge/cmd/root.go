@@ -43,11 +43,13 @@ func Execute() {
 func init() {
 	cobra.OnInitialize(initConfig)
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.guardiand.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file in YAML or TOML format (default is $HOME/.guardiand.yaml)")
 	rootCmd.AddCommand(guardiand.BridgeCmd)
 	rootCmd.AddCommand(guardiand.KeygenCmd)
 	rootCmd.AddCommand(guardiand.AdminCmd)
 	rootCmd.AddCommand(guardiand.TemplateCmd)
+	rootCmd.AddCommand(guardiand.ConfigCmd)
+	rootCmd.AddCommand(guardiand.LoadtestCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(debug.DebugCmd)
 }
@@ -67,13 +69,16 @@ func initConfig() {
 
 		// Search config in home directory with name ".guardiand" (without extension).
 		viper.AddConfigPath(home)
-		viper.SetConfigName(".guardiand.yaml")
+		viper.SetConfigName(".guardiand")
 	}
 
 	viper.AutomaticEnv() // read in environment variables that match
 
-	// If a config file is found, read it in.
+	// If a config file is found, read it in. An explicitly specified config file must exist.
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
+	} else if cfgFile != "" {
+		fmt.Printf("failed to read config file: %v\n", err)
+		os.Exit(1)
 	}
 }
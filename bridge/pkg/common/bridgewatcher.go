@@ -1,5 +0,0 @@
-package common
-
-type BridgeWatcher interface {
-	WatchLockups(events chan *ChainLock) error
-}
@@ -0,0 +1,93 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+	"github.com/certusone/wormhole/bridge/pkg/readiness"
+	"github.com/certusone/wormhole/bridge/pkg/supervisor"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+// ErrVAAAlreadyExecuted is returned (wrapped) by a VAASubmitter if the target chain has already
+// executed the VAA, which is expected when several guardians race to submit it.
+var ErrVAAAlreadyExecuted = errors.New("VAA already executed")
+
+// Chain is a module implementing support for a Wormhole-connected chain.
+//
+// Chains are registered with a ChainRegistry, which guardiand uses to start watchers and register readiness
+// components, and the processor uses to submit VAAs to their target chains.
+type Chain interface {
+	// ID returns the Wormhole chain ID.
+	ID() vaa.ChainID
+	// Name returns a short, lower-case name of the chain module, used to name its supervisor runnables.
+	Name() string
+	// ReadinessComponent returns the readiness component the watcher sets once it's synced.
+	ReadinessComponent() readiness.Component
+	// Watcher returns a runnable that watches the chain for lockups (sent to lockC)
+	// and guardian set changes (sent to setC), and reports the chain's network status to stats.
+	Watcher(lockC chan *ChainLock, setC chan *GuardianSet, stats NetworkStatsReporter) supervisor.Runnable
+	// Submitter returns the VAASubmitter for this chain, or nil if the node does not submit VAAs to it.
+	Submitter() VAASubmitter
+}
+
+// BatchWatcher is implemented by chains whose watcher can report the lockups confirmed in a block together,
+// allowing them to be signed as a single batch VAA.
+type BatchWatcher interface {
+	// BatchWatcher returns a runnable like Chain.Watcher, which sends the lockups confirmed in each block to
+	// lockBatchC instead of sending them to lockC one by one.
+	BatchWatcher(lockBatchC chan *ChainLockBatch, setC chan *GuardianSet, stats NetworkStatsReporter) supervisor.Runnable
+}
+
+// NetworkStatsReporter is the handle through which a chain watcher reports its chain's network status,
+// which is broadcast in the node's heartbeats.
+type NetworkStatsReporter interface {
+	// SetNetworkStats sets the chain's current network status. The Id field is set automatically.
+	SetNetworkStats(data *gossipv1.Heartbeat_Network)
+}
+
+// VAASubmitter submits signed VAAs to a chain.
+type VAASubmitter interface {
+	// SubmitVAA submits a signed VAA and returns a human-readable transaction reference.
+	// If the VAA was already executed, the returned error wraps ErrVAAAlreadyExecuted.
+	SubmitVAA(ctx context.Context, v *vaa.VAA) (string, error)
+}
+
+// ChainRegistry is the set of chains a node is connected to.
+type ChainRegistry struct {
+	chains map[vaa.ChainID]Chain
+}
+
+func NewChainRegistry() *ChainRegistry {
+	return &ChainRegistry{chains: map[vaa.ChainID]Chain{}}
+}
+
+// Register adds a chain to the registry. Registering the same chain ID twice is a programming error and panics.
+// Registration is not thread safe and must happen before the registry is passed to any consumers.
+func (r *ChainRegistry) Register(c Chain) {
+	if _, ok := r.chains[c.ID()]; ok {
+		panic(fmt.Sprintf("chain %s (%d) registered twice", c.Name(), c.ID()))
+	}
+
+	r.chains[c.ID()] = c
+}
+
+// Get returns the chain with the given ID, or nil if it is not registered.
+func (r *ChainRegistry) Get(id vaa.ChainID) Chain {
+	return r.chains[id]
+}
+
+// All returns all registered chains, ordered by chain ID.
+func (r *ChainRegistry) All() []Chain {
+	cs := make([]Chain, 0, len(r.chains))
+	for _, c := range r.chains {
+		cs = append(cs, c)
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		return cs[i].ID() < cs[j].ID()
+	})
+	return cs
+}
@@ -26,4 +26,19 @@ type ChainLock struct {
 	TokenDecimals uint8
 
 	Amount *big.Int
+
+	// Payload is the sender-provided payload for the recipient, or nil for a plain transfer.
+	Payload []byte
+	// Fee is the part of Amount paid to the relayer, or nil if none. Transfers with a fee always carry a payload.
+	Fee *big.Int
+}
+
+// ChainLockBatch is the set of all lockups in a single confirmed source chain block, which the processor signs as
+// a single batch VAA in addition to a transfer VAA per lockup.
+type ChainLockBatch struct {
+	SourceChain vaa.ChainID
+	BlockHash   common.Hash
+	Timestamp   time.Time
+
+	Locks []*ChainLock
 }
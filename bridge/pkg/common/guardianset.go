@@ -1,7 +1,11 @@
 package common
 
 import (
+	"sync"
+
 	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
 )
 
 // Matching constants:
@@ -9,7 +13,7 @@ import (
 //
 // The Eth and Terra contracts do not specify a maximum number and support more than that,
 // but presumably, chain-specific transaction size limits will apply at some point (untested).
-const MaxGuardianCount = 19
+const MaxGuardianCount = vaa.MaxGuardianCount
 
 type GuardianSet struct {
 	// Guardian's public key hashes truncated by the ETH standard hashing mechanism (20 bytes).
@@ -39,3 +43,30 @@ func (g *GuardianSet) KeyIndex(addr common.Address) (int, bool) {
 
 	return -1, false
 }
+
+// GuardianSetState is a thread-safe holder of the current guardian set. The processor is the source of truth
+// and updates it on every guardian set change, such that other components (like p2p) can verify guardian signatures.
+type GuardianSetState struct {
+	mu      sync.Mutex
+	current *GuardianSet
+}
+
+func NewGuardianSetState() *GuardianSetState {
+	return &GuardianSetState{}
+}
+
+// Set sets the current guardian set.
+func (st *GuardianSetState) Set(gs *GuardianSet) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.current = gs
+}
+
+// Get returns the current guardian set, or nil if it hasn't been initialized yet.
+func (st *GuardianSetState) Get() *GuardianSet {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.current
+}
@@ -0,0 +1,24 @@
+package common
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+// MessagePublication is an arbitrary message published by an emitter on a chain, as observed by a chain watcher.
+// It's the message passing equivalent of ChainLock and results in a vaa.BodyMessage VAA.
+type MessagePublication struct {
+	TxHash    common.Hash
+	Timestamp time.Time
+
+	EmitterChain   vaa.ChainID
+	EmitterAddress vaa.Address
+	Sequence       uint64
+
+	ConsistencyLevel uint8
+
+	Payload []byte
+}
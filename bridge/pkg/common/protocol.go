@@ -0,0 +1,5 @@
+package common
+
+// ProtocolVersion is the version of the gossip protocol implemented by this node, sent in every GossipMessage.
+// Messages without a version were sent by nodes predating protocol versioning and are treated as version 0.
+const ProtocolVersion = 1
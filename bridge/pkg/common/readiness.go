@@ -2,9 +2,8 @@ package common
 
 import "github.com/certusone/wormhole/bridge/pkg/readiness"
 
+// Liveness-only components. Chain watchers report progress using their chain module's readiness component.
 const (
-	ReadinessEthSyncing    readiness.Component = "ethSyncing"
-	ReadinessSolanaSyncing readiness.Component = "solanaSyncing"
-	ReadinessTerraSyncing  readiness.Component = "terraSyncing"
-	ReadinessQtumSyncing    readiness.Component = "qtumSyncing"
+	LivenessP2PHeartbeat readiness.Component = "p2pHeartbeat"
+	LivenessProcessor    readiness.Component = "processor"
 )
@@ -0,0 +1,109 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+// SignatureStatus is the outcome of verifying a single signature on a VAA.
+type SignatureStatus int
+
+const (
+	// SignatureValid means the signature was made by the guardian at its index.
+	SignatureValid SignatureStatus = iota
+	// SignatureWrongSigner means the signature was not made by the guardian at its index, or is malformed.
+	SignatureWrongSigner
+	// SignatureIndexOutOfRange means the guardian set has no guardian at the signature's index.
+	SignatureIndexOutOfRange
+	// SignatureDuplicate means an earlier valid signature on the VAA has the same index.
+	SignatureDuplicate
+)
+
+func (s SignatureStatus) String() string {
+	switch s {
+	case SignatureValid:
+		return "valid"
+	case SignatureWrongSigner:
+		return "wrong_signer"
+	case SignatureIndexOutOfRange:
+		return "index_out_of_range"
+	case SignatureDuplicate:
+		return "duplicate"
+	default:
+		return fmt.Sprintf("unknown signature status: %d", int(s))
+	}
+}
+
+// SignatureResult is the verification result of a single signature on a VAA.
+type SignatureResult struct {
+	// Index of the guardian the signature claims to be made by.
+	Index  uint8
+	Status SignatureStatus
+	// Signer is the address recovered from the signature, or the zero address if the signature is malformed.
+	Signer common.Address
+}
+
+// SignatureReport is the detailed result of verifying a VAA's signatures against a guardian set.
+type SignatureReport struct {
+	// Signatures are the results for each of the VAA's signatures, in order.
+	Signatures []SignatureResult
+	// ValidSignatures is the number of valid signatures by distinct guardians.
+	ValidSignatures int
+	// Quorum is the number of valid signatures required by the guardian set.
+	Quorum int
+	// GuardianSetMatches is whether the VAA specifies the guardian set it was verified against.
+	GuardianSetMatches bool
+}
+
+// QuorumMet returns whether the VAA has a quorum of valid signatures by the guardian set it specifies.
+// Invalid signatures don't prevent quorum, but contracts may reject VAAs which carry them.
+func (r *SignatureReport) QuorumMet() bool {
+	return r.GuardianSetMatches && r.ValidSignatures >= r.Quorum
+}
+
+// VerifySignatures verifies each of the VAA's signatures against the guardian set. Unlike vaa.VAA.VerifySignatures,
+// it doesn't stop at the first invalid signature and evaluates quorum.
+func VerifySignatures(v *vaa.VAA, gs *GuardianSet) (*SignatureReport, error) {
+	digest, err := v.SigningMsg()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &SignatureReport{
+		Signatures:         make([]SignatureResult, len(v.Signatures)),
+		Quorum:             CalculateQuorum(len(gs.Keys)),
+		GuardianSetMatches: v.GuardianSetIndex == gs.Index,
+	}
+
+	seen := make(map[uint8]bool, len(v.Signatures))
+	for i, sig := range v.Signatures {
+		res := SignatureResult{Index: sig.Index}
+
+		if pk, err := crypto.Ecrecover(digest.Bytes(), sig.Signature[:]); err == nil {
+			res.Signer = common.BytesToAddress(crypto.Keccak256(pk[1:])[12:])
+		}
+
+		// An index only counts as seen once it has a valid signature, such that an invalid signature can't shadow
+		// a valid one for the same index.
+		switch {
+		case int(sig.Index) >= len(gs.Keys):
+			res.Status = SignatureIndexOutOfRange
+		case res.Signer == (common.Address{}) || res.Signer != gs.Keys[sig.Index]:
+			res.Status = SignatureWrongSigner
+		case seen[sig.Index]:
+			res.Status = SignatureDuplicate
+		default:
+			res.Status = SignatureValid
+			r.ValidSignatures++
+			seen[sig.Index] = true
+		}
+
+		r.Signatures[i] = res
+	}
+
+	return r, nil
+}
@@ -0,0 +1,105 @@
+package common
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+func TestVerifySignatures(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 4)
+	gs := &GuardianSet{Index: 3}
+	for i := range keys {
+		var err error
+		keys[i], err = crypto.GenerateKey()
+		require.NoError(t, err)
+		gs.Keys = append(gs.Keys, crypto.PubkeyToAddress(keys[i].PublicKey))
+	}
+	outsider, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	newVAA := func() *vaa.VAA {
+		return &vaa.VAA{
+			Version:          vaa.SupportedVAAVersion,
+			GuardianSetIndex: 3,
+			Timestamp:        time.Unix(2837, 0),
+			Payload: &vaa.BodyTransfer{
+				SourceChain:   vaa.ChainIDEthereum,
+				TargetChain:   vaa.ChainIDSolana,
+				TargetAddress: vaa.Address{2, 1, 3},
+				Asset:         &vaa.AssetMeta{Chain: vaa.ChainIDEthereum, Address: vaa.Address{9, 2, 4}},
+				Amount:        big.NewInt(29),
+			},
+		}
+	}
+
+	t.Run("quorum", func(t *testing.T) {
+		v := newVAA()
+		v.AddSignature(keys[0], 0)
+		v.AddSignature(keys[1], 1)
+		v.AddSignature(keys[3], 3)
+
+		r, err := VerifySignatures(v, gs)
+		require.NoError(t, err)
+		assert.Equal(t, 3, r.ValidSignatures)
+		assert.Equal(t, 3, r.Quorum)
+		assert.True(t, r.QuorumMet())
+		for _, s := range r.Signatures {
+			assert.Equal(t, SignatureValid, s.Status)
+			assert.Equal(t, gs.Keys[s.Index], s.Signer)
+		}
+	})
+
+	t.Run("invalid signatures", func(t *testing.T) {
+		v := newVAA()
+		v.AddSignature(keys[0], 0)
+		v.AddSignature(keys[0], 0)
+		v.AddSignature(outsider, 1)
+		v.AddSignature(keys[1], 1)
+		v.AddSignature(keys[2], 7)
+		v.AddSignature(keys[3], 3)
+		v.Signatures = append(v.Signatures, &vaa.Signature{Index: 2})
+
+		r, err := VerifySignatures(v, gs)
+		require.NoError(t, err)
+		var statuses []SignatureStatus
+		for _, s := range r.Signatures {
+			statuses = append(statuses, s.Status)
+		}
+		assert.Equal(t, []SignatureStatus{
+			SignatureValid,
+			SignatureDuplicate,
+			SignatureWrongSigner,
+			SignatureValid, // Not shadowed by the invalid signature for the same index.
+			SignatureIndexOutOfRange,
+			SignatureValid,
+			SignatureWrongSigner,
+		}, statuses)
+		assert.Equal(t, crypto.PubkeyToAddress(outsider.PublicKey), r.Signatures[2].Signer)
+		assert.Equal(t, common.Address{}, r.Signatures[6].Signer)
+		assert.Equal(t, 3, r.ValidSignatures)
+		assert.True(t, r.QuorumMet())
+	})
+
+	t.Run("wrong guardian set", func(t *testing.T) {
+		v := newVAA()
+		v.GuardianSetIndex = 2
+		for i, k := range keys {
+			v.AddSignature(k, uint8(i))
+		}
+
+		r, err := VerifySignatures(v, gs)
+		require.NoError(t, err)
+		assert.Equal(t, 4, r.ValidSignatures)
+		assert.False(t, r.GuardianSetMatches)
+		assert.False(t, r.QuorumMet())
+	})
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/mr-tron/base58"
 
 	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
 
@@ -117,13 +116,9 @@ func Wallet() *hdwallet.Wallet {
 // Base58ToEthAddress converts a Solana base58 address to a 32-byte vaa.Address.
 // Panics if the input data is invalid - intended for use on constants.
 func MustBase58ToEthAddress(address string) (res vaa.Address) {
-	b, err := base58.Decode(address)
+	res, err := vaa.ParseAddress(vaa.ChainIDSolana, address)
 	if err != nil {
 		panic(err)
 	}
-
-	if n := copy(res[:], b); n != 32 {
-		panic("invalid length")
-	}
 	return
 }
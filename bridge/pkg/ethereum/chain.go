@@ -0,0 +1,73 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	eth_common "github.com/ethereum/go-ethereum/common"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/devnet"
+	"github.com/certusone/wormhole/bridge/pkg/readiness"
+	"github.com/certusone/wormhole/bridge/pkg/supervisor"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+// ReadinessSyncing is the readiness component of the Ethereum watcher.
+const ReadinessSyncing readiness.Component = "ethSyncing"
+
+// Chain is the Ethereum chain module.
+type Chain struct {
+	url              string
+	bridge           eth_common.Address
+	minConfirmations uint64
+
+	// devnetMode enables VAA submission to the hardcoded Ethereum devnet. Ethereum is expensive and guardians
+	// cannot be expected to pay the fees, so in production, users submit VAAs themselves.
+	devnetMode bool
+}
+
+func NewChain(url string, bridge eth_common.Address, minConfirmations uint64, devnetMode bool) *Chain {
+	return &Chain{url: url, bridge: bridge, minConfirmations: minConfirmations, devnetMode: devnetMode}
+}
+
+func (c *Chain) ID() vaa.ChainID {
+	return vaa.ChainIDEthereum
+}
+
+func (c *Chain) Name() string {
+	return "eth"
+}
+
+func (c *Chain) ReadinessComponent() readiness.Component {
+	return ReadinessSyncing
+}
+
+func (c *Chain) Watcher(lockC chan *common.ChainLock, setC chan *common.GuardianSet, stats common.NetworkStatsReporter) supervisor.Runnable {
+	return NewEthBridgeWatcher(c.url, c.bridge, c.minConfirmations, lockC, setC, stats).Run
+}
+
+func (c *Chain) BatchWatcher(lockBatchC chan *common.ChainLockBatch, setC chan *common.GuardianSet, stats common.NetworkStatsReporter) supervisor.Runnable {
+	return NewEthBridgeBatchWatcher(c.url, c.bridge, c.minConfirmations, lockBatchC, setC, stats).Run
+}
+
+func (c *Chain) Submitter() common.VAASubmitter {
+	if !c.devnetMode {
+		return nil
+	}
+	return c
+}
+
+// SubmitVAA submits a VAA to the Ethereum devnet using well-known accounts and contract addresses.
+func (c *Chain) SubmitVAA(ctx context.Context, v *vaa.VAA) (string, error) {
+	tx, err := devnet.SubmitVAA(ctx, c.url, v)
+	if err != nil {
+		if strings.Contains(err.Error(), "VAA was already executed") {
+			return "", fmt.Errorf("%w: %v", common.ErrVAAAlreadyExecuted, err)
+		}
+		return "", err
+	}
+
+	return tx.Hash().Hex(), nil
+}
@@ -7,10 +7,10 @@ import (
 
 // PadAddress creates 32-byte VAA.Address from 20-byte Ethereum addresses by adding 12 0-bytes at the left
 func PadAddress(address common.Address) vaa.Address {
-	paddedAddress := common.LeftPadBytes(address[:], 32)
-
-	addr := vaa.Address{}
-	copy(addr[:], paddedAddress)
-
+	addr, err := vaa.ParseAddress(vaa.ChainIDEthereum, address.Hex())
+	if err != nil {
+		// Hex always returns a valid address.
+		panic(err)
+	}
 	return addr
 }
@@ -3,7 +3,6 @@ package ethereum
 import (
 	"context"
 	"fmt"
-	"github.com/certusone/wormhole/bridge/pkg/p2p"
 	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
 	"math/big"
 	"sync"
@@ -11,9 +10,11 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	eth_common "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"go.uber.org/zap"
 
@@ -58,6 +59,9 @@ var (
 		}, []string{"operation"})
 )
 
+// tokensLockedTopic is the topic of LogTokensLocked events.
+var tokensLockedTopic = crypto.Keccak256Hash([]byte("LogTokensLocked(uint8,uint8,uint8,bytes32,bytes32,bytes32,uint256,uint32)"))
+
 func init() {
 	prometheus.MustRegister(ethConnectionErrors)
 	prometheus.MustRegister(ethLockupsFound)
@@ -78,21 +82,34 @@ type (
 
 		lockChan chan *common.ChainLock
 		setChan  chan *common.GuardianSet
+
+		// lockBatchChan receives the lockups confirmed in each block instead of lockChan, if set.
+		lockBatchChan chan *common.ChainLockBatch
+
+		stats common.NetworkStatsReporter
 	}
 
 	pendingLock struct {
-		lock   *common.ChainLock
-		height uint64
+		lock      *common.ChainLock
+		height    uint64
+		blockHash eth_common.Hash
 	}
 )
 
-func NewEthBridgeWatcher(url string, bridge eth_common.Address, minConfirmations uint64, lockEvents chan *common.ChainLock, setEvents chan *common.GuardianSet) *EthBridgeWatcher {
-	return &EthBridgeWatcher{url: url, bridge: bridge, minConfirmations: minConfirmations, lockChan: lockEvents, setChan: setEvents, pendingLocks: map[eth_common.Hash]*pendingLock{}}
+func NewEthBridgeWatcher(url string, bridge eth_common.Address, minConfirmations uint64, lockEvents chan *common.ChainLock, setEvents chan *common.GuardianSet, stats common.NetworkStatsReporter) *EthBridgeWatcher {
+	return &EthBridgeWatcher{url: url, bridge: bridge, minConfirmations: minConfirmations, lockChan: lockEvents, setChan: setEvents, stats: stats, pendingLocks: map[eth_common.Hash]*pendingLock{}}
+}
+
+// NewEthBridgeBatchWatcher returns a watcher which sends the lockups confirmed in each block as a batch.
+func NewEthBridgeBatchWatcher(url string, bridge eth_common.Address, minConfirmations uint64, lockBatchEvents chan *common.ChainLockBatch, setEvents chan *common.GuardianSet, stats common.NetworkStatsReporter) *EthBridgeWatcher {
+	e := NewEthBridgeWatcher(url, bridge, minConfirmations, nil, setEvents, stats)
+	e.lockBatchChan = lockBatchEvents
+	return e
 }
 
 func (e *EthBridgeWatcher) Run(ctx context.Context) error {
 	// Initialize gossip metrics (we want to broadcast the address even if we're not yet syncing)
-	p2p.DefaultRegistry.SetNetworkStats(vaa.ChainIDEthereum, &gossipv1.Heartbeat_Network{
+	e.stats.SetNetworkStats(&gossipv1.Heartbeat_Network{
 		BridgeAddress: e.bridge.Hex(),
 	})
 
@@ -179,19 +196,7 @@ func (e *EthBridgeWatcher) Run(ctx context.Context) error {
 					return
 				}
 
-				lock := &common.ChainLock{
-					TxHash:        ev.Raw.TxHash,
-					Timestamp:     time.Unix(int64(b.Time()), 0),
-					Nonce:         ev.Nonce,
-					SourceAddress: ev.Sender,
-					TargetAddress: ev.Recipient,
-					SourceChain:   vaa.ChainIDEthereum,
-					TargetChain:   vaa.ChainID(ev.TargetChain),
-					TokenChain:    vaa.ChainID(ev.TokenChain),
-					TokenAddress:  ev.Token,
-					TokenDecimals: ev.TokenDecimals,
-					Amount:        ev.Amount,
-				}
+				lock := lockFromEvent(ev, time.Unix(int64(b.Time()), 0))
 
 				logger.Info("found new lockup transaction", zap.Stringer("tx", ev.Raw.TxHash),
 					zap.Uint64("block", ev.Raw.BlockNumber))
@@ -200,8 +205,9 @@ func (e *EthBridgeWatcher) Run(ctx context.Context) error {
 
 				e.pendingLocksGuard.Lock()
 				e.pendingLocks[ev.Raw.TxHash] = &pendingLock{
-					lock:   lock,
-					height: ev.Raw.BlockNumber,
+					lock:      lock,
+					height:    ev.Raw.BlockNumber,
+					blockHash: ev.Raw.BlockHash,
 				}
 				e.pendingLocksGuard.Unlock()
 			case ev := <-guardianSetC:
@@ -251,8 +257,9 @@ func (e *EthBridgeWatcher) Run(ctx context.Context) error {
 				start := time.Now()
 				logger.Info("processing new header", zap.Stringer("block", ev.Number))
 				currentEthHeight.Set(float64(ev.Number.Int64()))
-				readiness.SetReady(common.ReadinessEthSyncing)
-				p2p.DefaultRegistry.SetNetworkStats(vaa.ChainIDEthereum, &gossipv1.Heartbeat_Network{
+				readiness.SetReady(ReadinessSyncing)
+				readiness.ReportProgress(ReadinessSyncing)
+				e.stats.SetNetworkStats(&gossipv1.Heartbeat_Network{
 					Height:        ev.Number.Int64(),
 					BridgeAddress: e.bridge.Hex(),
 				})
@@ -260,6 +267,8 @@ func (e *EthBridgeWatcher) Run(ctx context.Context) error {
 				e.pendingLocksGuard.Lock()
 
 				blockNumberU := ev.Number.Uint64()
+				// Heights of the blocks with newly confirmed lockups, by block hash.
+				confirmedBlocks := map[eth_common.Hash]uint64{}
 				for hash, pLock := range e.pendingLocks {
 
 					// Transaction was dropped and never picked up again
@@ -275,12 +284,34 @@ func (e *EthBridgeWatcher) Run(ctx context.Context) error {
 						logger.Debug("lockup confirmed", zap.Stringer("tx", pLock.lock.TxHash),
 							zap.Stringer("block", ev.Number))
 						delete(e.pendingLocks, hash)
-						e.lockChan <- pLock.lock
 						ethLockupsConfirmed.Inc()
+
+						if e.lockBatchChan == nil {
+							e.lockChan <- pLock.lock
+							continue
+						}
+						confirmedBlocks[pLock.blockHash] = pLock.height
 					}
 				}
 
 				e.pendingLocksGuard.Unlock()
+
+				for blockHash, height := range confirmedBlocks {
+					b, err := e.fetchLockBatch(ctx, c, f, blockHash, height)
+					if err != nil {
+						errC <- err
+						return
+					}
+					if b == nil {
+						logger.Info("skipping lockup batch of block that is no longer canonical",
+							zap.Stringer("block_hash", blockHash), zap.Uint64("block", height))
+						continue
+					}
+					logger.Debug("lockup batch confirmed", zap.Stringer("block_hash", b.BlockHash),
+						zap.Int("lockups", len(b.Locks)))
+					e.lockBatchChan <- b
+				}
+
 				logger.Info("processed new header", zap.Stringer("block", ev.Number),
 					zap.Duration("took", time.Since(start)))
 			}
@@ -295,6 +326,75 @@ func (e *EthBridgeWatcher) Run(ctx context.Context) error {
 	}
 }
 
+// lockFromEvent converts a LogTokensLocked event to a ChainLock, using the given block timestamp.
+func lockFromEvent(ev *abi.AbiLogTokensLocked, timestamp time.Time) *common.ChainLock {
+	return &common.ChainLock{
+		TxHash:        ev.Raw.TxHash,
+		Timestamp:     timestamp,
+		Nonce:         ev.Nonce,
+		SourceAddress: ev.Sender,
+		TargetAddress: ev.Recipient,
+		SourceChain:   vaa.ChainIDEthereum,
+		TargetChain:   vaa.ChainID(ev.TargetChain),
+		TokenChain:    vaa.ChainID(ev.TokenChain),
+		TokenAddress:  ev.Token,
+		TokenDecimals: ev.TokenDecimals,
+		Amount:        ev.Amount,
+	}
+}
+
+// fetchLockBatch returns the batch of all lockups in the given confirmed block. The lockups are queried by block
+// hash rather than taken from the log subscription, which may have missed some, such that every guardian commits
+// to the same set. Returns nil if the block is no longer part of the canonical chain.
+func (e *EthBridgeWatcher) fetchLockBatch(ctx context.Context, c *ethclient.Client, f *abi.AbiFilterer, blockHash eth_common.Hash, height uint64) (*common.ChainLockBatch, error) {
+	timeout, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	msm := time.Now()
+	header, err := c.HeaderByNumber(timeout, new(big.Int).SetUint64(height))
+	queryLatency.WithLabelValues("header_by_number").Observe(time.Since(msm).Seconds())
+	if err != nil {
+		ethConnectionErrors.WithLabelValues("header_by_number_error").Inc()
+		return nil, fmt.Errorf("failed to request header for block %d: %w", height, err)
+	}
+	if header.Hash() != blockHash {
+		return nil, nil
+	}
+
+	msm = time.Now()
+	logs, err := c.FilterLogs(timeout, ethereum.FilterQuery{
+		BlockHash: &blockHash,
+		Addresses: []eth_common.Address{e.bridge},
+		Topics:    [][]eth_common.Hash{{tokensLockedTopic}},
+	})
+	queryLatency.WithLabelValues("filter_logs").Observe(time.Since(msm).Seconds())
+	if err != nil {
+		ethConnectionErrors.WithLabelValues("filter_logs_error").Inc()
+		return nil, fmt.Errorf("failed to request lockups in block %s: %w", blockHash.Hex(), err)
+	}
+
+	b := &common.ChainLockBatch{
+		SourceChain: vaa.ChainIDEthereum,
+		BlockHash:   blockHash,
+		Timestamp:   time.Unix(int64(header.Time), 0),
+	}
+	for _, l := range logs {
+		if l.Removed {
+			continue
+		}
+		ev, err := f.ParseLogTokensLocked(l)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse lockup in block %s: %w", blockHash.Hex(), err)
+		}
+		b.Locks = append(b.Locks, lockFromEvent(ev, b.Timestamp))
+	}
+	if len(b.Locks) == 0 {
+		return nil, nil
+	}
+
+	return b, nil
+}
+
 // Fetch the current guardian set ID and guardian set from the chain.
 func FetchCurrentGuardianSet(ctx context.Context, rpcURL string, bridgeContract eth_common.Address) (uint32, *abi.WormholeGuardianSet, error) {
 	c, err := ethclient.DialContext(ctx, rpcURL)
@@ -0,0 +1,287 @@
+// Package fake implements a chain watcher which emits synthetic lockups and message publications instead of
+// watching an actual chain, for load testing the processor and gossip network. Synthetic events are signed like
+// real ones, so it must only ever be enabled in devnet mode.
+package fake
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/supervisor"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+var (
+	lockupsEmittedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "wormhole_fake_lockups_emitted_total",
+			Help: "Total number of synthetic lockups emitted by the fake watcher",
+		})
+	messagesEmittedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "wormhole_fake_messages_emitted_total",
+			Help: "Total number of synthetic message publications emitted by the fake watcher",
+		})
+	lockupsBehind = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "wormhole_fake_lockups_behind",
+			Help: "Number of synthetic events that are due, but were not yet accepted by the processor",
+		})
+)
+
+func init() {
+	prometheus.MustRegister(lockupsEmittedTotal)
+	prometheus.MustRegister(messagesEmittedTotal)
+	prometheus.MustRegister(lockupsBehind)
+}
+
+// Route is a source and target chain pair of synthetic lockups, or the emitter chain of synthetic message
+// publications if Message is set. Routes are picked with a probability proportional to their weight.
+type Route struct {
+	Source  vaa.ChainID
+	Target  vaa.ChainID
+	Message bool
+	Weight  uint
+}
+
+func (r Route) String() string {
+	if r.Message {
+		return fmt.Sprintf("%s:message=%d", r.Source, r.Weight)
+	}
+	return fmt.Sprintf("%s:%s=%d", r.Source, r.Target, r.Weight)
+}
+
+// DefaultRoutes are lockups from Ethereum to Solana and back, in equal parts.
+var DefaultRoutes = []Route{
+	{Source: vaa.ChainIDEthereum, Target: vaa.ChainIDSolana, Weight: 1},
+	{Source: vaa.ChainIDSolana, Target: vaa.ChainIDEthereum, Weight: 1},
+}
+
+// ParseRoute parses a route of the form <source>:<target>[=<weight>] using chain names, like "ethereum:solana=3".
+// A target of "message" emits message publications instead of lockups, like "ethereum:message". The weight
+// defaults to 1.
+func ParseRoute(s string) (Route, error) {
+	chains, weight := s, "1"
+	if i := strings.IndexByte(s, '='); i != -1 {
+		chains, weight = s[:i], s[i+1:]
+	}
+
+	parts := strings.Split(chains, ":")
+	if len(parts) != 2 {
+		return Route{}, fmt.Errorf("invalid route %q: expected <source>:<target>[=<weight>]", s)
+	}
+
+	source, err := vaa.ChainIDFromString(parts[0])
+	if err != nil {
+		return Route{}, fmt.Errorf("invalid route %q: %w", s, err)
+	}
+	w, err := strconv.ParseUint(weight, 10, 32)
+	if err != nil || w == 0 {
+		return Route{}, fmt.Errorf("invalid route %q: weight must be a positive integer", s)
+	}
+	if parts[1] == "message" {
+		return Route{Source: source, Message: true, Weight: uint(w)}, nil
+	}
+	target, err := vaa.ChainIDFromString(parts[1])
+	if err != nil {
+		return Route{}, fmt.Errorf("invalid route %q: %w", s, err)
+	}
+
+	return Route{Source: source, Target: target, Weight: uint(w)}, nil
+}
+
+// ParseRoutes parses a list of routes (see ParseRoute). An empty list results in DefaultRoutes.
+func ParseRoutes(ss []string) ([]Route, error) {
+	if len(ss) == 0 {
+		return DefaultRoutes, nil
+	}
+
+	routes := make([]Route, len(ss))
+	for i, s := range ss {
+		r, err := ParseRoute(s)
+		if err != nil {
+			return nil, err
+		}
+		routes[i] = r
+	}
+	return routes, nil
+}
+
+// Generator deterministically derives synthetic events from a seed and a sequence number. Generators with
+// the same seed and routes return identical events, which allows fake watchers on different guardians to
+// observe the same events and reach quorum on them.
+type Generator struct {
+	seed   int64
+	routes []Route
+	total  uint
+}
+
+func NewGenerator(seed int64, routes []Route) (*Generator, error) {
+	var total uint
+	for _, r := range routes {
+		total += r.Weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("at least one route with a positive weight is required")
+	}
+
+	return &Generator{seed: seed, routes: routes, total: total}, nil
+}
+
+// event returns the tx hash, route and random source of the n-th synthetic event.
+func (g *Generator) event(n uint64) (ethcommon.Hash, Route, *rand.Rand) {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(g.seed))
+	binary.BigEndian.PutUint64(id[8:], n)
+	h := crypto.Keccak256Hash(id[:])
+
+	// Everything else is derived from the hash, such that neighbouring events are unrelated.
+	r := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(h[:8]))))
+
+	w := uint(r.Int63n(int64(g.total)))
+	route := g.routes[len(g.routes)-1]
+	for _, rt := range g.routes {
+		if w < rt.Weight {
+			route = rt
+			break
+		}
+		w -= rt.Weight
+	}
+
+	return h, route, r
+}
+
+// Lockup returns the n-th synthetic event if it is a lockup, or nil if it is a message publication. Its nonce
+// is the lower 32 bits of n.
+func (g *Generator) Lockup(n uint64, timestamp time.Time) *common.ChainLock {
+	h, route, r := g.event(n)
+	if route.Message {
+		return nil
+	}
+
+	k := &common.ChainLock{
+		TxHash:        h,
+		Timestamp:     timestamp,
+		Nonce:         uint32(n),
+		SourceChain:   route.Source,
+		TargetChain:   route.Target,
+		TokenChain:    route.Source,
+		TokenDecimals: 8,
+		Amount:        big.NewInt(r.Int63n(1e12) + 1),
+	}
+	r.Read(k.SourceAddress[:])
+	r.Read(k.TargetAddress[:])
+	r.Read(k.TokenAddress[:])
+
+	return k
+}
+
+// Message returns the n-th synthetic event if it is a message publication, or nil if it is a lockup. Its
+// sequence is n.
+func (g *Generator) Message(n uint64, timestamp time.Time) *common.MessagePublication {
+	h, route, r := g.event(n)
+	if !route.Message {
+		return nil
+	}
+
+	m := &common.MessagePublication{
+		TxHash:           h,
+		Timestamp:        timestamp,
+		EmitterChain:     route.Source,
+		Sequence:         n,
+		ConsistencyLevel: 1,
+		Payload:          make([]byte, r.Intn(256)+1),
+	}
+	r.Read(m.EmitterAddress[:])
+	r.Read(m.Payload)
+
+	return m
+}
+
+// Watcher emits the events of a Generator at a fixed rate.
+//
+// Event n is due n/rate seconds after the Unix epoch and uses that time as its timestamp. Watchers on different
+// guardians therefore emit the same events, regardless of when they were started.
+type Watcher struct {
+	g     *Generator
+	rate  float64
+	lockC chan *common.ChainLock
+	msgC  chan *common.MessagePublication
+}
+
+// NewWatcher creates a watcher emitting rate events per second, sending lockups to lockC and message publications
+// to msgC.
+func NewWatcher(g *Generator, rate float64, lockC chan *common.ChainLock, msgC chan *common.MessagePublication) *Watcher {
+	return &Watcher{g: g, rate: rate, lockC: lockC, msgC: msgC}
+}
+
+// Due returns the sequence number of the latest event that is due at time t.
+func (w *Watcher) Due(t time.Time) uint64 {
+	return uint64(float64(t.UnixNano()) / 1e9 * w.rate)
+}
+
+// Time returns the time event n is due at.
+func (w *Watcher) Time(n uint64) time.Time {
+	return time.Unix(0, int64(float64(n)/w.rate*1e9))
+}
+
+func (w *Watcher) Run(ctx context.Context) error {
+	if w.rate <= 0 {
+		return fmt.Errorf("invalid rate %g: must be positive", w.rate)
+	}
+
+	logger := supervisor.Logger(ctx)
+	supervisor.Signal(ctx, supervisor.SignalHealthy)
+
+	next := w.Due(time.Now()) + 1
+	logger.Info("emitting synthetic events",
+		zap.Float64("rate", w.rate), zap.Uint64("first", next), zap.Int("routes", len(w.g.routes)))
+
+	timer := time.NewTimer(time.Until(w.Time(next)))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		// Emit every event that is due. If the processor can't keep up, we fall behind rather than skip events,
+		// which shows up as increasing latency.
+		due := w.Due(time.Now())
+		for ; next <= due; next++ {
+			lockupsBehind.Set(float64(due - next + 1))
+			if k := w.g.Lockup(next, w.Time(next)); k != nil {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case w.lockC <- k:
+					lockupsEmittedTotal.Inc()
+				}
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case w.msgC <- w.g.Message(next, w.Time(next)):
+				messagesEmittedTotal.Inc()
+			}
+		}
+		lockupsBehind.Set(0)
+
+		timer.Reset(time.Until(w.Time(next)))
+	}
+}
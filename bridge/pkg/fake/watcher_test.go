@@ -0,0 +1,111 @@
+package fake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Route
+		wantErr bool
+	}{
+		{in: "ethereum:solana", want: Route{Source: vaa.ChainIDEthereum, Target: vaa.ChainIDSolana, Weight: 1}},
+		{in: "terra:ethereum=3", want: Route{Source: vaa.ChainIDTerra, Target: vaa.ChainIDEthereum, Weight: 3}},
+		{in: "solana:message=2", want: Route{Source: vaa.ChainIDSolana, Message: true, Weight: 2}},
+		{in: "ethereum", wantErr: true},
+		{in: "ethereum:solana:terra", wantErr: true},
+		{in: "ethereum:mars", wantErr: true},
+		{in: "ethereum:solana=0", wantErr: true},
+		{in: "ethereum:solana=-1", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			r, err := ParseRoute(tc.in)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, r)
+		})
+	}
+}
+
+func TestGeneratorIsDeterministic(t *testing.T) {
+	g1, err := NewGenerator(42, DefaultRoutes)
+	require.NoError(t, err)
+	g2, err := NewGenerator(42, DefaultRoutes)
+	require.NoError(t, err)
+	g3, err := NewGenerator(43, DefaultRoutes)
+	require.NoError(t, err)
+
+	ts := time.Unix(1605105600, 0)
+	assert.Equal(t, g1.Lockup(7, ts), g2.Lockup(7, ts))
+	assert.NotEqual(t, g1.Lockup(7, ts).TxHash, g1.Lockup(8, ts).TxHash)
+	assert.NotEqual(t, g1.Lockup(7, ts).TxHash, g3.Lockup(7, ts).TxHash)
+	assert.Equal(t, uint32(7), g1.Lockup(7, ts).Nonce)
+}
+
+func TestGeneratorRouteWeights(t *testing.T) {
+	g, err := NewGenerator(0, []Route{
+		{Source: vaa.ChainIDEthereum, Target: vaa.ChainIDSolana, Weight: 3},
+		{Source: vaa.ChainIDSolana, Target: vaa.ChainIDTerra, Weight: 1},
+	})
+	require.NoError(t, err)
+
+	counts := map[vaa.ChainID]int{}
+	for n := uint64(0); n < 4000; n++ {
+		k := g.Lockup(n, time.Time{})
+		counts[k.SourceChain]++
+		assert.Equal(t, k.SourceChain, k.TokenChain)
+	}
+
+	assert.InDelta(t, 3000, counts[vaa.ChainIDEthereum], 150)
+	assert.InDelta(t, 1000, counts[vaa.ChainIDSolana], 150)
+}
+
+func TestGeneratorMessages(t *testing.T) {
+	g, err := NewGenerator(0, []Route{
+		{Source: vaa.ChainIDEthereum, Target: vaa.ChainIDSolana, Weight: 1},
+		{Source: vaa.ChainIDTerra, Message: true, Weight: 1},
+	})
+	require.NoError(t, err)
+
+	var lockups, messages int
+	for n := uint64(0); n < 1000; n++ {
+		k, m := g.Lockup(n, time.Time{}), g.Message(n, time.Time{})
+		// Every event is either a lockup or a message.
+		require.True(t, (k == nil) != (m == nil), "event %d", n)
+		if k != nil {
+			lockups++
+			continue
+		}
+		messages++
+		assert.Equal(t, vaa.ChainID(vaa.ChainIDTerra), m.EmitterChain)
+		assert.Equal(t, n, m.Sequence)
+		assert.NotEmpty(t, m.Payload)
+	}
+
+	assert.InDelta(t, 500, lockups, 75)
+	assert.InDelta(t, 500, messages, 75)
+}
+
+func TestNewGeneratorRequiresRoutes(t *testing.T) {
+	_, err := NewGenerator(0, nil)
+	assert.Error(t, err)
+}
+
+func TestWatcherSchedule(t *testing.T) {
+	w := NewWatcher(nil, 4, nil, nil)
+
+	assert.Equal(t, time.Unix(10, 250000000), w.Time(41))
+	assert.Equal(t, uint64(41), w.Due(time.Unix(10, 400000000)))
+}
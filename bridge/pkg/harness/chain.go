@@ -0,0 +1,83 @@
+package harness
+
+import (
+	"context"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/readiness"
+	"github.com/certusone/wormhole/bridge/pkg/supervisor"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+// ReadinessFakeSyncing is the readiness component of FakeChain.
+const ReadinessFakeSyncing readiness.Component = "fakeSyncing"
+
+// FakeChain is a chain module whose watcher emits lockups and guardian sets injected by the test
+// instead of watching an actual chain. It doubles as the node's guardian set source.
+type FakeChain struct {
+	id    vaa.ChainID
+	lockC chan *common.ChainLock
+	setC  chan *common.GuardianSet
+}
+
+func NewFakeChain(id vaa.ChainID) *FakeChain {
+	return &FakeChain{
+		id:    id,
+		lockC: make(chan *common.ChainLock),
+		setC:  make(chan *common.GuardianSet),
+	}
+}
+
+func (c *FakeChain) ID() vaa.ChainID {
+	return c.id
+}
+
+func (c *FakeChain) Name() string {
+	return "fake" + c.id.String()
+}
+
+func (c *FakeChain) ReadinessComponent() readiness.Component {
+	return ReadinessFakeSyncing
+}
+
+func (c *FakeChain) Watcher(lockC chan *common.ChainLock, setC chan *common.GuardianSet, stats common.NetworkStatsReporter) supervisor.Runnable {
+	return func(ctx context.Context) error {
+		readiness.SetReady(ReadinessFakeSyncing)
+		supervisor.Signal(ctx, supervisor.SignalHealthy)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case k := <-c.lockC:
+				lockC <- k
+			case gs := <-c.setC:
+				setC <- gs
+			}
+		}
+	}
+}
+
+func (c *FakeChain) Submitter() common.VAASubmitter {
+	return nil
+}
+
+// InjectLock makes the watcher emit the given lockup, as if it had been confirmed on chain.
+func (c *FakeChain) InjectLock(ctx context.Context, k *common.ChainLock) error {
+	select {
+	case c.lockC <- k:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetGuardianSet makes the watcher emit the given guardian set, as if it had been changed on chain.
+func (c *FakeChain) SetGuardianSet(ctx context.Context, gs *common.GuardianSet) error {
+	select {
+	case c.setC <- gs:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,683 @@
+// Package harness runs a network of guardian nodes in a single process, for testing gossip, quorum and
+// guardian set transitions without a devnet. It also backs the guardiand loadtest command.
+//
+// Nodes run the actual p2p and processor runnables, connected by a libp2p mocknet. Chains are replaced by a
+// FakeChain per node, which the test uses to inject lockups and guardian set changes. Every VAA a node reaches
+// quorum on is recorded and can be awaited using Node.WaitForVAA.
+//
+// Faults are simulated at the network level (Partition, Heal) and per node (inbound and outbound observation
+// filters, and lockup mutators for byzantine guardians).
+//
+// The p2p heartbeat registry is process-global, so all nodes announce the same guardian address and their
+// heartbeats are flagged as unverified. Heartbeats don't take part in consensus, so tests don't rely on them.
+package harness
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/multiformats/go-multiaddr"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/devnet"
+	"github.com/certusone/wormhole/bridge/pkg/p2p"
+	"github.com/certusone/wormhole/bridge/pkg/processor"
+	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+	"github.com/certusone/wormhole/bridge/pkg/publicrpc"
+	"github.com/certusone/wormhole/bridge/pkg/supervisor"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+// networkID is the p2p network identifier used by all harness nodes.
+const networkID = "/wormhole/harness"
+
+// Filter decides whether an observation is delivered. Returning false drops it.
+type Filter func(m *gossipv1.SignedObservation) bool
+
+// DropAll is a Filter that drops every observation.
+func DropAll(*gossipv1.SignedObservation) bool {
+	return false
+}
+
+// DropRandomly returns a Filter that drops observations with the given probability,
+// using a deterministic random source.
+func DropRandomly(rate float64, seed int64) Filter {
+	var mu sync.Mutex
+	r := rand.New(rand.NewSource(seed))
+	return func(*gossipv1.SignedObservation) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return r.Float64() >= rate
+	}
+}
+
+// T is the subset of testing.TB used by the harness, such that it can be used outside of tests.
+type T interface {
+	Fatalf(format string, args ...interface{})
+	Cleanup(f func())
+}
+
+// Harness is a network of in-process guardian nodes.
+type Harness struct {
+	t      T
+	ctx    context.Context
+	cancel context.CancelFunc
+	mn     mocknet.Mocknet
+	logger *zap.Logger
+	onVAA  func(n *Node, v *vaa.VAA)
+	chains []vaa.ChainID
+
+	Nodes []*Node
+}
+
+// Node is a single guardian node of the harness.
+type Node struct {
+	Index int
+	// Key is the node's guardian key.
+	Key  *ecdsa.PrivateKey
+	Host host.Host
+	// Chain is the node's only chain module and guardian set source.
+	Chain *FakeChain
+
+	p2p       *p2p.Node
+	gst       *common.GuardianSetState
+	timelineC chan *processor.TimelineRequest
+	msgC      chan *common.MessagePublication
+	batchC    chan *common.ChainLockBatch
+	injectC   chan *vaa.VAA
+
+	mu sync.Mutex
+	// vaas are the VAAs the node reached quorum on, in order
+	vaas []*vaa.VAA
+	// vaaNotify is closed and replaced whenever a VAA is added
+	vaaNotify chan struct{}
+
+	inbound  Filter
+	outbound Filter
+	mutate   func(k *common.ChainLock)
+	// peers are the guardians on the node's side of a partition, or nil if the network isn't partitioned
+	peers map[ethcommon.Address]bool
+}
+
+// Option configures a Harness.
+type Option func(h *Harness)
+
+// WithLogger makes the nodes log to the given logger instead of discarding their logs.
+func WithLogger(logger *zap.Logger) Option {
+	return func(h *Harness) {
+		h.logger = logger
+	}
+}
+
+// WithVAAHandler calls f for every VAA a node reaches quorum on, before it is recorded. It is called from the
+// node's recorder and must not block.
+func WithVAAHandler(f func(n *Node, v *vaa.VAA)) Option {
+	return func(h *Harness) {
+		h.onVAA = f
+	}
+}
+
+// WithChains connects the nodes to additional chains, like the target chains of injected lockups.
+func WithChains(ids ...vaa.ChainID) Option {
+	return func(h *Harness) {
+		h.chains = append(h.chains, ids...)
+	}
+}
+
+// New creates a harness with n guardian nodes using the deterministic devnet guardian and node keys. The nodes are not
+// connected or running until Start is called. The harness is shut down when the test finishes.
+func New(t T, n int, opts ...Option) *Harness {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &Harness{
+		t:      t,
+		ctx:    ctx,
+		cancel: cancel,
+		mn:     mocknet.New(ctx),
+		logger: zap.NewNop(),
+	}
+	for _, o := range opts {
+		o(h)
+	}
+
+	t.Cleanup(func() {
+		h.cancel()
+		for _, host := range h.mn.Hosts() {
+			_ = host.Close()
+		}
+	})
+
+	for i := 0; i < n; i++ {
+		addr, err := multiaddr.NewMultiaddr(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", 8999+i))
+		if err != nil {
+			panic(err)
+		}
+		host, err := h.mn.AddPeer(devnet.DeterministicP2PPrivKeyByIndex(int64(i)), addr)
+		if err != nil {
+			t.Fatalf("failed to create mocknet peer: %v", err)
+		}
+
+		h.Nodes = append(h.Nodes, &Node{
+			Index:     i,
+			Key:       devnet.DeterministicEcdsaKeyByIndex(crypto.S256(), uint64(i)),
+			Host:      host,
+			Chain:     NewFakeChain(vaa.ChainIDEthereum),
+			gst:       common.NewGuardianSetState(),
+			vaaNotify: make(chan struct{}),
+		})
+	}
+
+	return h
+}
+
+// Start connects all nodes, starts them, makes them observe a guardian set consisting of all nodes and
+// waits for the gossip mesh to form.
+func (h *Harness) Start() {
+	if err := h.mn.LinkAll(); err != nil {
+		h.t.Fatalf("failed to link peers: %v", err)
+	}
+
+	for _, n := range h.Nodes {
+		if err := h.startNode(n); err != nil {
+			h.t.Fatalf("failed to start node %d: %v", n.Index, err)
+		}
+	}
+
+	// Pubsub only announces its subscriptions to peers which already run pubsub when connecting,
+	// so we can only connect the nodes once all of them have been started.
+	h.connectAll()
+
+	h.SetGuardianSet(h.GuardianSet(0, h.all()...))
+	h.WaitForMesh()
+}
+
+func (h *Harness) startNode(n *Node) error {
+	logger := h.logger.Named(fmt.Sprintf("node%d", n.Index))
+
+	// Observations pass through the node's filters between processor and p2p.
+	procSendC := make(chan []byte)
+	netSendC := make(chan []byte)
+	netObsvC := make(chan *gossipv1.SignedObservation, 50)
+	procObsvC := make(chan *gossipv1.SignedObservation, 50)
+	lockC := make(chan *common.ChainLock)
+	setC := make(chan *common.GuardianSet)
+	vaaC := make(chan *vaa.VAA)
+	quorumC := make(chan *vaa.VAA)
+	n.injectC = make(chan *vaa.VAA)
+	n.timelineC = make(chan *processor.TimelineRequest)
+	n.msgC = make(chan *common.MessagePublication)
+	n.batchC = make(chan *common.ChainLockBatch)
+
+	// Every node is connected to Solana, which stores the VAAs its program accepts. The other chains' watchers
+	// aren't needed.
+	chains := common.NewChainRegistry()
+	chains.Register(n.Chain)
+	for _, id := range append([]vaa.ChainID{vaa.ChainIDSolana}, h.chains...) {
+		if chains.Get(id) == nil {
+			chains.Register(NewFakeChain(id))
+		}
+	}
+
+	var err error
+	// Our own messages are published to every subscribed peer rather than just our mesh peers. Tests partition and
+	// heal the network at will, and this keeps delivery independent of how quickly the gossipsub mesh recovers.
+	n.p2p, err = p2p.New(h.ctx, n.Host, networkID, false, nil, netObsvC, netSendC,
+		publicrpc.HeartbeatStreamMultiplexer(logger), n.Key, n.gst, "", "", 0, fmt.Sprintf("node%d", n.Index),
+		pubsub.WithFloodPublish(true))
+	if err != nil {
+		return err
+	}
+
+	supervisor.New(h.ctx, logger, func(ctx context.Context) error {
+		if err := supervisor.Run(ctx, "p2p", n.p2p.Run); err != nil {
+			return err
+		}
+		if err := supervisor.Run(ctx, n.Chain.Name()+"watch", n.Chain.Watcher(lockC, setC, p2p.DefaultRegistry.ChainStats(n.Chain.ID()))); err != nil {
+			return err
+		}
+		if err := supervisor.Run(ctx, "filter", n.runFilters(procSendC, netSendC, netObsvC, procObsvC)); err != nil {
+			return err
+		}
+		if err := supervisor.Run(ctx, "vaas", n.runRecorder(quorumC, h.onVAA)); err != nil {
+			return err
+		}
+		if err := supervisor.Run(ctx, "solvaa", discardVAAs(vaaC)); err != nil {
+			return err
+		}
+
+		p := processor.NewProcessor(ctx, lockC, n.batchC, n.msgC, setC, procSendC, procObsvC, vaaC, quorumC, n.injectC, n.timelineC, n.Key,
+			chains, n.gst, false, 0, "")
+		if err := supervisor.Run(ctx, "processor", p.Run); err != nil {
+			return err
+		}
+
+		<-ctx.Done()
+		return nil
+	}, supervisor.WithPropagatePanic)
+
+	return nil
+}
+
+// runFilters passes outbound and inbound observations through the node's filters.
+func (n *Node) runFilters(procSendC <-chan []byte, netSendC chan<- []byte,
+	netObsvC <-chan *gossipv1.SignedObservation, procObsvC chan<- *gossipv1.SignedObservation) supervisor.Runnable {
+	return func(ctx context.Context) error {
+		supervisor.Signal(ctx, supervisor.SignalHealthy)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case b := <-procSendC:
+				var msg gossipv1.GossipMessage
+				if err := proto.Unmarshal(b, &msg); err != nil {
+					return fmt.Errorf("processor sent invalid message: %w", err)
+				}
+				if f := n.filter(false); f != nil && !f(msg.GetSignedObservation()) {
+					continue
+				}
+				select {
+				case netSendC <- b:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			case m := <-netObsvC:
+				if !n.reaches(m.Addr) {
+					continue
+				}
+				if f := n.filter(true); f != nil && !f(m) {
+					continue
+				}
+				select {
+				case procObsvC <- m:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	}
+}
+
+// runRecorder records the VAAs the processor reached quorum on and passes them to onVAA, if set.
+func (n *Node) runRecorder(quorumC <-chan *vaa.VAA, onVAA func(n *Node, v *vaa.VAA)) supervisor.Runnable {
+	return func(ctx context.Context) error {
+		supervisor.Signal(ctx, supervisor.SignalHealthy)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case v := <-quorumC:
+				if onVAA != nil {
+					onVAA(n, v)
+				}
+				n.mu.Lock()
+				n.vaas = append(n.vaas, v)
+				close(n.vaaNotify)
+				n.vaaNotify = make(chan struct{})
+				n.mu.Unlock()
+			}
+		}
+	}
+}
+
+// discardVAAs drains the VAAs the processor would store on Solana. Nodes record every VAA that reached quorum
+// using runRecorder instead.
+func discardVAAs(vaaC <-chan *vaa.VAA) supervisor.Runnable {
+	return func(ctx context.Context) error {
+		supervisor.Signal(ctx, supervisor.SignalHealthy)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-vaaC:
+			}
+		}
+	}
+}
+
+func (n *Node) filter(inbound bool) Filter {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if inbound {
+		return n.inbound
+	}
+	return n.outbound
+}
+
+// reaches returns whether the guardian with the given address is on the node's side of a partition.
+func (n *Node) reaches(addr []byte) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.peers == nil || n.peers[ethcommon.BytesToAddress(addr)]
+}
+
+// SetInboundFilter filters observations received from other nodes. A nil filter delivers every observation.
+func (n *Node) SetInboundFilter(f Filter) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.inbound = f
+}
+
+// SetOutboundFilter filters the node's own observations before they are broadcast. The node still counts its
+// own signature. A nil filter broadcasts every observation.
+func (n *Node) SetOutboundFilter(f Filter) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.outbound = f
+}
+
+// SetByzantine makes the node observe a modified copy of every injected lockup, such that it signs and broadcasts
+// a conflicting digest. A nil mutator restores honest behavior.
+func (n *Node) SetByzantine(mutate func(k *common.ChainLock)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.mutate = mutate
+}
+
+// VAAs returns the VAAs the node reached quorum on so far.
+func (n *Node) VAAs() []*vaa.VAA {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]*vaa.VAA(nil), n.vaas...)
+}
+
+// WaitForVAA waits until the node reaches quorum on a VAA matching the given predicate and returns it.
+func (n *Node) WaitForVAA(ctx context.Context, match func(v *vaa.VAA) bool) (*vaa.VAA, error) {
+	for {
+		n.mu.Lock()
+		for _, v := range n.vaas {
+			if match(v) {
+				n.mu.Unlock()
+				return v, nil
+			}
+		}
+		notify := n.vaaNotify
+		n.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("node %d: %w", n.Index, ctx.Err())
+		}
+	}
+}
+
+// Timeline returns the node's aggregation timeline of the VAA with the given digest, or nil if it's unknown.
+func (n *Node) Timeline(ctx context.Context, digest ethcommon.Hash) (*processor.Timeline, error) {
+	respC := make(chan *processor.Timeline, 1)
+	select {
+	case n.timelineC <- &processor.TimelineRequest{Digest: hex.EncodeToString(digest.Bytes()), ResponseC: respC}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("node %d: %w", n.Index, ctx.Err())
+	}
+
+	select {
+	case t := <-respC:
+		return t, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("node %d: %w", n.Index, ctx.Err())
+	}
+}
+
+// ForLock returns a predicate matching transfer VAAs created from the given lockup.
+func ForLock(k *common.ChainLock) func(v *vaa.VAA) bool {
+	return func(v *vaa.VAA) bool {
+		var t *vaa.BodyTransfer
+		switch p := v.Payload.(type) {
+		case *vaa.BodyTransfer:
+			t = p
+		case *vaa.BodyTransferWithPayload:
+			t = &p.BodyTransfer
+		default:
+			return false
+		}
+		return t.Nonce == k.Nonce &&
+			t.SourceChain == k.SourceChain &&
+			t.TargetChain == k.TargetChain &&
+			t.Amount.Cmp(k.Amount) == 0 &&
+			v.Timestamp.Equal(k.Timestamp)
+	}
+}
+
+func (h *Harness) all() []int {
+	idx := make([]int, len(h.Nodes))
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
+
+// GuardianSet returns a guardian set with the given index consisting of the given nodes' keys.
+func (h *Harness) GuardianSet(index uint32, nodes ...int) *common.GuardianSet {
+	gs := &common.GuardianSet{Index: index}
+	for _, i := range nodes {
+		gs.Keys = append(gs.Keys, crypto.PubkeyToAddress(h.Nodes[i].Key.PublicKey))
+	}
+	return gs
+}
+
+// SetGuardianSet makes every node observe the given guardian set and waits until they use it.
+func (h *Harness) SetGuardianSet(gs *common.GuardianSet) {
+	ctx, cancel := context.WithTimeout(h.ctx, 10*time.Second)
+	defer cancel()
+
+	for _, n := range h.Nodes {
+		if err := n.Chain.SetGuardianSet(ctx, gs); err != nil {
+			h.t.Fatalf("failed to set guardian set on node %d: %v", n.Index, err)
+		}
+	}
+
+	// The processor sets the guardian set state when it handles the update, and handles lockups after
+	// guardian set updates that were sent earlier.
+	for _, n := range h.Nodes {
+		for {
+			if cur := n.gst.Get(); cur != nil && cur.Index == gs.Index {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				h.t.Fatalf("node %d did not apply guardian set %d", n.Index, gs.Index)
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+}
+
+// InjectLock makes every node observe the given lockup. Byzantine nodes observe a modified copy.
+func (h *Harness) InjectLock(k *common.ChainLock) {
+	ctx, cancel := context.WithTimeout(h.ctx, 10*time.Second)
+	defer cancel()
+
+	for _, n := range h.Nodes {
+		lock := *k
+		n.mu.Lock()
+		mutate := n.mutate
+		n.mu.Unlock()
+		if mutate != nil {
+			mutate(&lock)
+		}
+
+		if err := n.Chain.InjectLock(ctx, &lock); err != nil {
+			h.t.Fatalf("failed to inject lockup on node %d: %v", n.Index, err)
+		}
+	}
+}
+
+// InjectMessage makes all nodes observe the given message publication.
+func (h *Harness) InjectMessage(m *common.MessagePublication) {
+	ctx, cancel := context.WithTimeout(h.ctx, 10*time.Second)
+	defer cancel()
+
+	for _, n := range h.Nodes {
+		select {
+		case n.msgC <- m:
+		case <-ctx.Done():
+			h.t.Fatalf("failed to inject message on node %d: %v", n.Index, ctx.Err())
+		}
+	}
+}
+
+// InjectGovernanceVAA makes all nodes sign the given governance VAA, as if it was injected via the admin socket.
+func (h *Harness) InjectGovernanceVAA(v *vaa.VAA) {
+	ctx, cancel := context.WithTimeout(h.ctx, 10*time.Second)
+	defer cancel()
+
+	for _, n := range h.Nodes {
+		// The processor adds our signature to the VAA, so every node needs its own copy.
+		c := *v
+		c.Signatures = nil
+		select {
+		case n.injectC <- &c:
+		case <-ctx.Done():
+			h.t.Fatalf("failed to inject governance VAA on node %d: %v", n.Index, ctx.Err())
+		}
+	}
+}
+
+// InjectLockBatch makes all nodes observe the given batch of lockups. Every other node observes the lockups
+// in reverse order, since watchers don't guarantee any order.
+func (h *Harness) InjectLockBatch(b *common.ChainLockBatch) {
+	ctx, cancel := context.WithTimeout(h.ctx, 10*time.Second)
+	defer cancel()
+
+	for _, n := range h.Nodes {
+		batch := *b
+		if n.Index%2 == 1 {
+			batch.Locks = make([]*common.ChainLock, len(b.Locks))
+			for i, k := range b.Locks {
+				batch.Locks[len(b.Locks)-1-i] = k
+			}
+		}
+
+		select {
+		case n.batchC <- &batch:
+		case <-ctx.Done():
+			h.t.Fatalf("failed to inject lockup batch on node %d: %v", n.Index, ctx.Err())
+		}
+	}
+}
+
+// WaitForMesh waits until every node sees every node it is connected to on the observation topic.
+func (h *Harness) WaitForMesh() {
+	ctx, cancel := context.WithTimeout(h.ctx, 30*time.Second)
+	defer cancel()
+
+	for _, n := range h.Nodes {
+		for {
+			if len(n.p2p.TopicPeers(p2p.TopicObservation)) >= len(n.Host.Network().Peers()) {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				h.t.Fatalf("node %d did not join the gossip mesh", n.Index)
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+	}
+}
+
+// Partition splits the network into the given groups of node indices. Nodes in different groups
+// are disconnected and cannot reconnect until Heal is called. Nodes not listed in any group are isolated.
+//
+// When a connection is torn down, pubsub may open a new stream on it before mocknet removes it, which then
+// outlives the connection. Nodes therefore also drop observations by guardians on the other side.
+func (h *Harness) Partition(groups ...[]int) {
+	group := make(map[int]int)
+	for g, nodes := range groups {
+		for _, i := range nodes {
+			group[i] = g + 1
+		}
+	}
+
+	for i, n := range h.Nodes {
+		peers := map[ethcommon.Address]bool{crypto.PubkeyToAddress(n.Key.PublicKey): true}
+		for j, other := range h.Nodes {
+			if group[i] != 0 && group[i] == group[j] {
+				peers[crypto.PubkeyToAddress(other.Key.PublicKey)] = true
+			}
+		}
+		n.mu.Lock()
+		n.peers = peers
+		n.mu.Unlock()
+	}
+
+	var split [][2]*Node
+	for i, a := range h.Nodes {
+		for j, b := range h.Nodes[i+1:] {
+			gi, gj := group[i], group[i+1+j]
+			if gi != 0 && gi == gj {
+				continue
+			}
+			// Unlink first, so that the nodes can't redial each other before the connection is closed.
+			_ = h.mn.UnlinkPeers(a.Host.ID(), b.Host.ID())
+			_ = h.mn.DisconnectPeers(a.Host.ID(), b.Host.ID())
+			split = append(split, [2]*Node{a, b})
+		}
+	}
+
+	// Connections are torn down asynchronously. Wait until both sides noticed, so that the gossip mesh
+	// reflects the partition once Partition returns.
+	ctx, cancel := context.WithTimeout(h.ctx, 10*time.Second)
+	defer cancel()
+
+	for _, pair := range split {
+		for pair[0].Host.Network().Connectedness(pair[1].Host.ID()) == network.Connected ||
+			pair[1].Host.Network().Connectedness(pair[0].Host.ID()) == network.Connected {
+			select {
+			case <-ctx.Done():
+				h.t.Fatalf("node %d is still connected to node %d", pair[0].Index, pair[1].Index)
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+	}
+}
+
+// Heal reconnects all nodes after a Partition and waits for the gossip mesh to re-form.
+func (h *Harness) Heal() {
+	for i, a := range h.Nodes {
+		for _, b := range h.Nodes[i+1:] {
+			if len(h.mn.LinksBetweenPeers(a.Host.ID(), b.Host.ID())) == 0 {
+				if _, err := h.mn.LinkPeers(a.Host.ID(), b.Host.ID()); err != nil {
+					h.t.Fatalf("failed to link nodes: %v", err)
+				}
+			}
+		}
+	}
+
+	for _, n := range h.Nodes {
+		n.mu.Lock()
+		n.peers = nil
+		n.mu.Unlock()
+	}
+
+	h.connectAll()
+	h.WaitForMesh()
+}
+
+// connectAll connects every pair of linked nodes which isn't connected yet, using a single connection per pair.
+func (h *Harness) connectAll() {
+	for i, a := range h.Nodes {
+		for _, b := range h.Nodes[i+1:] {
+			if a.Host.Network().Connectedness(b.Host.ID()) == network.Connected {
+				continue
+			}
+			if _, err := h.mn.ConnectPeers(a.Host.ID(), b.Host.ID()); err != nil {
+				h.t.Fatalf("failed to connect node %d to node %d: %v", a.Index, b.Index, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package harness
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bridge_common "github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+const (
+	// quorumTimeout bounds the time for a healthy network to reach quorum.
+	quorumTimeout = 20 * time.Second
+	// noQuorumTimeout is how long we wait to assert that a node does *not* reach quorum.
+	noQuorumTimeout = 3 * time.Second
+)
+
+func testLock(nonce uint32) *bridge_common.ChainLock {
+	return &bridge_common.ChainLock{
+		TxHash:        common.HexToHash("0x06f541f5ecfc43407c31587aa6ac3a689e8960f36dc23c332db5510dfc6a4063"),
+		Timestamp:     time.Unix(1605105600, 0),
+		Nonce:         nonce,
+		SourceAddress: vaa.Address{1},
+		TargetAddress: vaa.Address{2},
+		SourceChain:   vaa.ChainIDEthereum,
+		TargetChain:   vaa.ChainIDSolana,
+		TokenChain:    vaa.ChainIDEthereum,
+		TokenAddress:  vaa.Address{3},
+		TokenDecimals: 8,
+		Amount:        big.NewInt(1000),
+	}
+}
+
+// requireConsistentVAAs waits for the given nodes to reach quorum on the lockup, and checks that they all
+// produced the same VAA with a quorum of valid signatures by the guardian set.
+func requireConsistentVAAs(t *testing.T, h *Harness, k *bridge_common.ChainLock, gs *bridge_common.GuardianSet, nodes ...int) []*vaa.VAA {
+	ctx, cancel := context.WithTimeout(context.Background(), quorumTimeout)
+	defer cancel()
+
+	var vaas []*vaa.VAA
+	for _, i := range nodes {
+		v, err := h.Nodes[i].WaitForVAA(ctx, ForLock(k))
+		require.NoError(t, err)
+		vaas = append(vaas, v)
+	}
+
+	want, err := vaas[0].SigningMsg()
+	require.NoError(t, err)
+
+	for i, v := range vaas {
+		digest, err := v.SigningMsg()
+		require.NoError(t, err)
+		assert.Equal(t, want, digest, "node %d produced a different VAA", nodes[i])
+		assert.Equal(t, gs.Index, v.GuardianSetIndex)
+		assert.GreaterOrEqual(t, len(v.Signatures), bridge_common.CalculateQuorum(len(gs.Keys)))
+		assert.True(t, v.VerifySignatures(gs.Keys), "node %d produced invalid signatures", nodes[i])
+	}
+
+	return vaas
+}
+
+func requireNoVAA(t *testing.T, h *Harness, k *bridge_common.ChainLock, nodes ...int) {
+	ctx, cancel := context.WithTimeout(context.Background(), noQuorumTimeout)
+	defer cancel()
+
+	for _, i := range nodes {
+		_, err := h.Nodes[i].WaitForVAA(ctx, ForLock(k))
+		assert.Error(t, err, "node %d unexpectedly reached quorum", i)
+	}
+}
+
+func TestQuorum(t *testing.T) {
+	h := New(t, 4)
+	h.Start()
+
+	k := testLock(1)
+	h.InjectLock(k)
+	requireConsistentVAAs(t, h, k, h.GuardianSet(0, 0, 1, 2, 3), 0, 1, 2, 3)
+}
+
+func TestTransferWithPayload(t *testing.T) {
+	h := New(t, 4)
+	h.Start()
+
+	k := testLock(1)
+	k.Fee = big.NewInt(10)
+	k.Payload = []byte("swap")
+	h.InjectLock(k)
+	for _, v := range requireConsistentVAAs(t, h, k, h.GuardianSet(0, 0, 1, 2, 3), 0, 1, 2, 3) {
+		require.IsType(t, &vaa.BodyTransferWithPayload{}, v.Payload)
+		p := v.Payload.(*vaa.BodyTransferWithPayload)
+		assert.Equal(t, k.Payload, p.Payload)
+		assert.Equal(t, 0, k.Fee.Cmp(p.Fee))
+	}
+}
+
+func TestLockBatch(t *testing.T) {
+	h := New(t, 4)
+	h.Start()
+
+	b := &bridge_common.ChainLockBatch{
+		SourceChain: vaa.ChainIDEthereum,
+		BlockHash:   common.HexToHash("0x1c6fa1dcc58e8b3f5d2fdc1c7d4c34bf4ad0aeb76a8e0b1ae2c0bd8c2e43a2b1"),
+		Timestamp:   time.Unix(1605105600, 0),
+	}
+	for i := 0; i < 3; i++ {
+		k := testLock(uint32(i))
+		k.TxHash = common.Hash{byte(i + 1)}
+		b.Locks = append(b.Locks, k)
+	}
+	h.InjectLockBatch(b)
+
+	ctx, cancel := context.WithTimeout(context.Background(), quorumTimeout)
+	defer cancel()
+	gs := h.GuardianSet(0, 0, 1, 2, 3)
+	var want *vaa.VAA
+	for _, n := range h.Nodes {
+		v, err := n.WaitForVAA(ctx, func(v *vaa.VAA) bool {
+			_, ok := v.Payload.(*vaa.BodyBatch)
+			return ok
+		})
+		require.NoError(t, err)
+		assert.True(t, v.VerifySignatures(gs.Keys), "node %d produced invalid signatures", n.Index)
+		if want == nil {
+			want = v
+		}
+		assert.Equal(t, want.Payload, v.Payload, "node %d produced a different batch", n.Index)
+	}
+
+	// No target chain verifies inclusion proofs yet, so every lockup is signed as a regular transfer VAA as well.
+	for _, k := range b.Locks {
+		_, err := h.Nodes[0].WaitForVAA(ctx, ForLock(k))
+		assert.NoError(t, err)
+	}
+
+	// Lockups are batched in order of their transaction hash, so they are in injection order.
+	var transfers []vaa.Payload
+	for _, k := range b.Locks {
+		transfers = append(transfers, &vaa.BodyTransfer{
+			Nonce:         k.Nonce,
+			SourceChain:   k.SourceChain,
+			TargetChain:   k.TargetChain,
+			SourceAddress: k.SourceAddress,
+			TargetAddress: k.TargetAddress,
+			Asset:         &vaa.AssetMeta{Chain: k.TokenChain, Address: k.TokenAddress, Decimals: k.TokenDecimals},
+			Amount:        k.Amount,
+		})
+	}
+	for i, transfer := range transfers {
+		proof, err := vaa.BatchProof(vaa.ChainIDEthereum, transfers, i)
+		require.NoError(t, err)
+		assert.NoError(t, vaa.VerifyBatchedTransfer(want, gs.Keys, transfer, i, proof))
+	}
+}
+
+func TestBridgePause(t *testing.T) {
+	h := New(t, 4)
+	h.Start()
+	gs := h.GuardianSet(0, 0, 1, 2, 3)
+
+	setPaused := func(paused bool, timestamp int64) {
+		h.InjectGovernanceVAA(&vaa.VAA{
+			Version:   vaa.SupportedVAAVersion,
+			Timestamp: time.Unix(timestamp, 0),
+			Payload:   &vaa.BodyBridgePause{ChainID: vaa.ChainIDSolana, Paused: paused},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), quorumTimeout)
+		defer cancel()
+		for _, n := range h.Nodes {
+			_, err := n.WaitForVAA(ctx, func(v *vaa.VAA) bool {
+				b, ok := v.Payload.(*vaa.BodyBridgePause)
+				return ok && b.Paused == paused
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	setPaused(true, 1605744545)
+	deferred := testLock(1)
+	h.InjectLock(deferred)
+	requireNoVAA(t, h, deferred, 0, 1, 2, 3)
+
+	// Lockups deferred during the pause are signed once it's over.
+	setPaused(false, 1605744546)
+	requireConsistentVAAs(t, h, deferred, gs, 0, 1, 2, 3)
+	k := testLock(2)
+	h.InjectLock(k)
+	requireConsistentVAAs(t, h, k, gs, 0, 1, 2, 3)
+}
+
+func TestMessage(t *testing.T) {
+	h := New(t, 4)
+	h.Start()
+
+	m := &bridge_common.MessagePublication{
+		TxHash:         common.HexToHash("0x06f541f5ecfc43407c31587aa6ac3a689e8960f36dc23c332db5510dfc6a4063"),
+		Timestamp:      time.Unix(1605105600, 0),
+		EmitterChain:   vaa.ChainIDSolana,
+		EmitterAddress: vaa.Address{4, 2},
+		Sequence:       7,
+		Payload:        []byte("hello"),
+	}
+	h.InjectMessage(m)
+
+	ctx, cancel := context.WithTimeout(context.Background(), quorumTimeout)
+	defer cancel()
+	gs := h.GuardianSet(0, 0, 1, 2, 3)
+	for _, n := range h.Nodes {
+		v, err := n.WaitForVAA(ctx, func(v *vaa.VAA) bool {
+			b, ok := v.Payload.(*vaa.BodyMessage)
+			return ok && b.Sequence == m.Sequence
+		})
+		require.NoError(t, err)
+		assert.Equal(t, m.Payload, v.Payload.(*vaa.BodyMessage).Payload)
+		assert.True(t, v.VerifySignatures(gs.Keys), "node %d produced invalid signatures", n.Index)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	h := New(t, 4)
+	h.Start()
+
+	k := testLock(1)
+	h.InjectLock(k)
+	vaas := requireConsistentVAAs(t, h, k, h.GuardianSet(0, 0, 1, 2, 3), 0)
+	digest, err := vaas[0].SigningMsg()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), quorumTimeout)
+	defer cancel()
+	tl, err := h.Nodes[0].Timeline(ctx, digest)
+	require.NoError(t, err)
+	require.NotNil(t, tl)
+
+	assert.False(t, tl.OurObservation.IsZero())
+	assert.False(t, tl.Quorum.Before(tl.OurObservation))
+	assert.False(t, tl.Submitted.Before(tl.Quorum))
+	assert.GreaterOrEqual(t, len(tl.Signatures), bridge_common.CalculateQuorum(4))
+	require.NotNil(t, tl.GuardianSetIndex)
+	assert.Equal(t, uint32(0), *tl.GuardianSetIndex)
+
+	tl, err = h.Nodes[0].Timeline(ctx, common.Hash{})
+	require.NoError(t, err)
+	assert.Nil(t, tl)
+}
+
+func TestPartition(t *testing.T) {
+	h := New(t, 4)
+	h.Start()
+
+	// With four guardians, quorum is three. The majority side reaches quorum, the isolated node doesn't.
+	h.Partition([]int{0, 1, 2}, []int{3})
+
+	k := testLock(1)
+	h.InjectLock(k)
+	requireConsistentVAAs(t, h, k, h.GuardianSet(0, 0, 1, 2, 3), 0, 1, 2)
+	requireNoVAA(t, h, k, 3)
+
+	h.Heal()
+
+	k = testLock(2)
+	h.InjectLock(k)
+	requireConsistentVAAs(t, h, k, h.GuardianSet(0, 0, 1, 2, 3), 0, 1, 2, 3)
+}
+
+func TestEvenSplitHasNoQuorum(t *testing.T) {
+	h := New(t, 4)
+	h.Start()
+
+	h.Partition([]int{0, 1}, []int{2, 3})
+
+	k := testLock(1)
+	h.InjectLock(k)
+	requireNoVAA(t, h, k, 0, 1, 2, 3)
+}
+
+func TestMessageDrops(t *testing.T) {
+	h := New(t, 4)
+	h.Start()
+
+	// Node 0 doesn't receive any observations, and node 1's observations are lost. Node 1 still hears
+	// from everyone else and reaches quorum on its own.
+	h.Nodes[0].SetInboundFilter(DropAll)
+	h.Nodes[1].SetOutboundFilter(DropAll)
+
+	k := testLock(1)
+	h.InjectLock(k)
+	vaas := requireConsistentVAAs(t, h, k, h.GuardianSet(0, 0, 1, 2, 3), 2, 3)
+	requireNoVAA(t, h, k, 0)
+
+	for _, v := range vaas {
+		for _, s := range v.Signatures {
+			assert.NotEqual(t, uint8(1), s.Index, "VAA includes a signature that was never broadcast")
+		}
+	}
+}
+
+func TestByzantineGuardian(t *testing.T) {
+	h := New(t, 4)
+	h.Start()
+
+	// Node 3 signs a lockup with an inflated amount.
+	h.Nodes[3].SetByzantine(func(k *bridge_common.ChainLock) {
+		k.Amount = new(big.Int).Mul(k.Amount, big.NewInt(1000))
+	})
+
+	k := testLock(1)
+	h.InjectLock(k)
+	vaas := requireConsistentVAAs(t, h, k, h.GuardianSet(0, 0, 1, 2, 3), 0, 1, 2)
+
+	for _, v := range vaas {
+		assert.Len(t, v.Signatures, 3)
+		for _, s := range v.Signatures {
+			assert.NotEqual(t, uint8(3), s.Index, "VAA includes the byzantine guardian's signature")
+		}
+	}
+
+	// The byzantine guardian's version never reaches quorum.
+	requireNoVAA(t, h, k, 3)
+}
+
+func TestGuardianSetTransition(t *testing.T) {
+	h := New(t, 5)
+	h.Start()
+
+	gs := h.GuardianSet(1, 0, 1, 2)
+	h.SetGuardianSet(gs)
+
+	k := testLock(1)
+	h.InjectLock(k)
+	requireConsistentVAAs(t, h, k, gs, 0, 1, 2, 3, 4)
+}
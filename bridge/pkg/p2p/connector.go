@@ -0,0 +1,256 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+)
+
+var (
+	p2pConnectedPeers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "wormhole_p2p_connected_peers",
+			Help: "Current number of connected p2p peers",
+		})
+	p2pConnectedGuardianPeers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "wormhole_p2p_connected_guardian_peers",
+			Help: "Current number of connected p2p peers run by a member of the current guardian set",
+		})
+	p2pDialAttempts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_p2p_dial_attempts_total",
+			Help: "Total number of attempts to (re)connect to bootstrap or known peers",
+		}, []string{"result"})
+)
+
+func init() {
+	prometheus.MustRegister(p2pConnectedPeers)
+	prometheus.MustRegister(p2pConnectedGuardianPeers)
+	prometheus.MustRegister(p2pDialAttempts)
+}
+
+const (
+	// How often the connector checks whether we're connected to enough peers.
+	connectorInterval = 10 * time.Second
+	// How often the persistent peerstore is written to disk.
+	peerstoreSaveInterval = time.Minute
+	// Timeout for a single dial attempt.
+	dialTimeout = 10 * time.Second
+)
+
+// guardianPeers tracks which peers are run by a guardian, as proven by a verified signed heartbeat.
+type guardianPeers struct {
+	mu    sync.Mutex
+	peers map[peer.ID]ethcommon.Address
+}
+
+func newGuardianPeers() *guardianPeers {
+	return &guardianPeers{peers: map[peer.ID]ethcommon.Address{}}
+}
+
+// observe records that the peer sent a heartbeat signed by the given guardian.
+func (g *guardianPeers) observe(p peer.ID, guardian ethcommon.Address) {
+	g.mu.Lock()
+	g.peers[p] = guardian
+	g.mu.Unlock()
+}
+
+// connected returns the number of connected peers run by a member of the given guardian set.
+func (g *guardianPeers) connected(h host.Host, gs *common.GuardianSet) int {
+	if gs == nil {
+		return 0
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	n := 0
+	for _, p := range h.Network().Peers() {
+		if addr, ok := g.peers[p]; ok {
+			if _, ok := gs.KeyIndex(addr); ok {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// connector keeps the node connected to at least minPeers guardian peers by re-dialing bootstrap and known peers,
+// with exponential backoff per peer, and persists known peers to disk.
+type connector struct {
+	h      host.Host
+	logger *zap.Logger
+
+	guardians *guardianPeers
+	gst       *common.GuardianSetState
+
+	minPeers      int
+	peerstorePath string
+
+	mu       sync.Mutex
+	backoffs map[peer.ID]*dialBackoff
+}
+
+type dialBackoff struct {
+	next time.Time
+	bo   backoff.BackOff
+}
+
+func newConnector(h host.Host, logger *zap.Logger, guardians *guardianPeers, gst *common.GuardianSetState,
+	minPeers int, peerstorePath string) *connector {
+	return &connector{
+		h:             h,
+		logger:        logger,
+		guardians:     guardians,
+		gst:           gst,
+		minPeers:      minPeers,
+		peerstorePath: peerstorePath,
+		backoffs:      map[peer.ID]*dialBackoff{},
+	}
+}
+
+// run periodically re-dials peers while the node is connected to less than minPeers guardian peers.
+// It persists the peerstore periodically and before returning.
+func (c *connector) run(ctx context.Context) {
+	tick := time.NewTicker(connectorInterval)
+	defer tick.Stop()
+	save := time.NewTicker(peerstoreSaveInterval)
+	defer save.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.save()
+			return
+		case <-tick.C:
+			c.updateMetrics()
+
+			// Peers are only known to be guardians once we received their heartbeat. Until then, we keep dialing
+			// all candidates we're not yet connected to.
+			n := c.guardians.connected(c.h, c.gst.Get())
+			if n < c.minPeers {
+				c.logger.Debug("connected to fewer guardian peers than required, reconnecting",
+					zap.Int("connected", n), zap.Int("min_peers", c.minPeers))
+				c.dialCandidates(ctx)
+			}
+		case <-save.C:
+			c.save()
+		}
+	}
+}
+
+// dialCandidates concurrently dials all known peers we're not connected to and whose backoff has expired.
+// It returns the number of successful connections.
+func (c *connector) dialCandidates(ctx context.Context) int {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+	)
+
+	now := time.Now()
+	for _, pi := range knownPeers(c.h) {
+		if c.h.Network().Connectedness(pi.ID) == network.Connected {
+			continue
+		}
+		if !c.ready(pi.ID, now) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(pi peer.AddrInfo) {
+			defer wg.Done()
+
+			dCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+			defer cancel()
+
+			if err := c.h.Connect(dCtx, pi); err != nil {
+				next := c.failed(pi.ID)
+				c.logger.Debug("failed to connect to peer",
+					zap.String("peer", pi.ID.String()), zap.Duration("retry_in", next), zap.Error(err))
+				p2pDialAttempts.WithLabelValues("failure").Inc()
+				return
+			}
+
+			c.succeeded(pi.ID)
+			c.logger.Info("connected to peer", zap.String("peer", pi.ID.String()))
+			p2pDialAttempts.WithLabelValues("success").Inc()
+
+			mu.Lock()
+			successes++
+			mu.Unlock()
+		}(pi)
+	}
+
+	wg.Wait()
+	c.updateMetrics()
+	return successes
+}
+
+func (c *connector) updateMetrics() {
+	p2pConnectedPeers.Set(float64(len(c.h.Network().Peers())))
+	p2pConnectedGuardianPeers.Set(float64(c.guardians.connected(c.h, c.gst.Get())))
+}
+
+// ready returns whether the peer's dial backoff has expired.
+func (c *connector) ready(id peer.ID, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.backoffs[id]
+	return !ok || !now.Before(b.next)
+}
+
+// failed records a failed dial attempt and returns the time until the next attempt.
+func (c *connector) failed(id peer.ID) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.backoffs[id]
+	if !ok {
+		bo := backoff.NewExponentialBackOff()
+		bo.InitialInterval = connectorInterval
+		bo.MaxInterval = 10 * time.Minute
+		bo.MaxElapsedTime = 0 // never give up
+		b = &dialBackoff{bo: bo}
+		c.backoffs[id] = b
+	}
+
+	d := b.bo.NextBackOff()
+	b.next = time.Now().Add(d)
+	return d
+}
+
+// succeeded resets the peer's dial backoff.
+func (c *connector) succeeded(id peer.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.backoffs, id)
+}
+
+// save writes the known peers to the persistent peerstore, if enabled.
+func (c *connector) save() {
+	if c.peerstorePath == "" {
+		return
+	}
+
+	peers := knownPeers(c.h)
+	if err := savePeerstore(c.peerstorePath, peers); err != nil {
+		c.logger.Warn("failed to save peerstore", zap.String("path", c.peerstorePath), zap.Error(err))
+		return
+	}
+
+	c.logger.Debug("saved peerstore", zap.String("path", c.peerstorePath), zap.Int("peers", len(peers)))
+}
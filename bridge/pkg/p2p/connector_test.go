@@ -0,0 +1,42 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+)
+
+func TestGuardianPeersConnected(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mn, err := mocknet.FullMeshConnected(ctx, 4)
+	require.NoError(t, err)
+
+	hosts := mn.Hosts()
+	guardian, stranger, bootstrap := hosts[1].ID(), hosts[2].ID(), hosts[3].ID()
+	guardianAddr, strangerAddr := ethcommon.Address{1}, ethcommon.Address{2}
+
+	g := newGuardianPeers()
+	g.observe(guardian, guardianAddr)
+	g.observe(stranger, strangerAddr)
+
+	assert.Equal(t, 0, g.connected(hosts[0], nil))
+
+	// Only peers run by a member of the current guardian set count. The bootstrap node never sent a heartbeat.
+	gs := &common.GuardianSet{Keys: []ethcommon.Address{guardianAddr}, Index: 1}
+	assert.Equal(t, 1, g.connected(hosts[0], gs))
+
+	gs = &common.GuardianSet{Keys: []ethcommon.Address{guardianAddr, strangerAddr}, Index: 2}
+	assert.Equal(t, 2, g.connected(hosts[0], gs))
+
+	require.NoError(t, mn.DisconnectPeers(hosts[0].ID(), guardian))
+	assert.Equal(t, 1, g.connected(hosts[0], gs))
+	assert.Len(t, hosts[0].Network().Peers(), 2, "bootstrap %s is still connected", bootstrap)
+}
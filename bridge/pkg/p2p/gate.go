@@ -0,0 +1,349 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p-core/control"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+)
+
+// In private network mode, the Gate only admits peers which are either on an operator-provided allowlist, or prove
+// control of a key in the current guardian set using the peer authentication protocol.
+//
+// Authentication happens after the connection is established: every node sends a signed PeerAuth message to the
+// peers it connects to. Peers which fail to authenticate within authTimeout are disconnected and denied further
+// connections for denyDuration. Until then, messages relayed by unauthenticated peers are ignored by the pubsub
+// validators.
+
+const (
+	// Maximum time for a peer to authenticate after connecting.
+	authTimeout = 10 * time.Second
+	// How long peers which failed to authenticate are denied connections.
+	denyDuration = 5 * time.Minute
+	// Maximum size of a PeerAuth message.
+	maxPeerAuthSize = 1024
+)
+
+var (
+	p2pGateDenied = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_p2p_gate_denied_total",
+			Help: "Total number of connections denied or closed by the private network gate, grouped by reason",
+		}, []string{"reason"})
+	p2pPeersAuthenticated = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "wormhole_p2p_peers_authenticated_total",
+			Help: "Total number of peers that proved control of a guardian key",
+		})
+)
+
+func init() {
+	prometheus.MustRegister(p2pGateDenied)
+	prometheus.MustRegister(p2pPeersAuthenticated)
+}
+
+// Gate is a libp2p ConnectionGater implementing private network mode.
+type Gate struct {
+	networkID string
+	allowlist map[peer.ID]bool
+	// guardianAuth specifies whether peers may be admitted by proving control of a guardian key.
+	guardianAuth bool
+	gst          *common.GuardianSetState
+
+	mu sync.Mutex
+	// authenticated maps authenticated peers to their guardian address
+	authenticated map[peer.ID]ethcommon.Address
+	// pending holds PeerAuth messages received before our guardian set was initialized
+	pending map[peer.ID]*gossipv1.PeerAuth
+	// denied maps denied peers to the time their denial expires
+	denied map[peer.ID]time.Time
+}
+
+// NewGate returns a Gate for the given network which admits the given allowlisted peers and, if guardianAuth
+// is set, peers authenticating with a guardian key in the current guardian set.
+func NewGate(networkID string, allowlist []peer.ID, guardianAuth bool, gst *common.GuardianSetState) *Gate {
+	g := &Gate{
+		networkID:     networkID,
+		allowlist:     make(map[peer.ID]bool, len(allowlist)),
+		guardianAuth:  guardianAuth,
+		gst:           gst,
+		authenticated: map[peer.ID]ethcommon.Address{},
+		pending:       map[peer.ID]*gossipv1.PeerAuth{},
+		denied:        map[peer.ID]time.Time{},
+	}
+	for _, p := range allowlist {
+		g.allowlist[p] = true
+	}
+	return g
+}
+
+// Admitted returns whether the given peer is allowlisted or has authenticated.
+func (g *Gate) Admitted(p peer.ID) bool {
+	if g.allowlist[p] {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.authenticated[p]
+	return ok
+}
+
+func (g *Gate) isDenied(p peer.ID) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	until, ok := g.denied[p]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(g.denied, p)
+		return false
+	}
+	return true
+}
+
+func (g *Gate) deny(p peer.ID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.denied[p] = time.Now().Add(denyDuration)
+	delete(g.pending, p)
+}
+
+func (g *Gate) InterceptPeerDial(p peer.ID) bool {
+	return !g.isDenied(p)
+}
+
+func (g *Gate) InterceptAddrDial(peer.ID, multiaddr.Multiaddr) bool {
+	return true
+}
+
+func (g *Gate) InterceptAccept(network.ConnMultiaddrs) bool {
+	return true
+}
+
+func (g *Gate) InterceptSecured(_ network.Direction, p peer.ID, _ network.ConnMultiaddrs) bool {
+	if g.allowlist[p] {
+		return true
+	}
+	if !g.guardianAuth {
+		p2pGateDenied.WithLabelValues("not_allowlisted").Inc()
+		return false
+	}
+	if g.isDenied(p) {
+		p2pGateDenied.WithLabelValues("denied").Inc()
+		return false
+	}
+	// Admitted provisionally, pending authentication.
+	return true
+}
+
+func (g *Gate) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+// authProtocol returns the libp2p protocol ID of the peer authentication protocol for the given network.
+func authProtocol(networkID string) protocol.ID {
+	return protocol.ID(networkID + "/auth/1")
+}
+
+// peerAuthDigest returns the digest signed by a guardian to authenticate the given peer.
+func peerAuthDigest(networkID string, p peer.ID) []byte {
+	return ethcrypto.Keccak256([]byte("wormhole-peer-auth"), []byte(networkID), []byte(p))
+}
+
+// signPeerAuth returns a PeerAuth message authenticating the given peer.
+func signPeerAuth(networkID string, p peer.ID, gk *ecdsa.PrivateKey) (*gossipv1.PeerAuth, error) {
+	sig, err := ethcrypto.Sign(peerAuthDigest(networkID, p), gk)
+	if err != nil {
+		return nil, err
+	}
+	return &gossipv1.PeerAuth{
+		GuardianAddr: ethcrypto.PubkeyToAddress(gk.PublicKey).Bytes(),
+		Signature:    sig,
+	}, nil
+}
+
+// verifyPeerAuth verifies that a PeerAuth message sent by the given peer was signed by a member of the guardian set.
+func verifyPeerAuth(networkID string, from peer.ID, m *gossipv1.PeerAuth, gs *common.GuardianSet) (ethcommon.Address, error) {
+	if gs == nil {
+		return ethcommon.Address{}, errors.New("guardian set is not initialized")
+	}
+
+	pk, err := ethcrypto.Ecrecover(peerAuthDigest(networkID, from), m.Signature)
+	if err != nil {
+		return ethcommon.Address{}, fmt.Errorf("failed to recover public key: %w", err)
+	}
+
+	signer := ethcommon.BytesToAddress(ethcrypto.Keccak256(pk[1:])[12:])
+	if !bytes.Equal(signer.Bytes(), m.GuardianAddr) {
+		return ethcommon.Address{}, fmt.Errorf("signer %s does not match guardian address %s",
+			signer.Hex(), ethcommon.BytesToAddress(m.GuardianAddr).Hex())
+	}
+
+	if _, ok := gs.KeyIndex(signer); !ok {
+		return ethcommon.Address{}, fmt.Errorf("signer %s is not in guardian set %d", signer.Hex(), gs.Index)
+	}
+
+	return signer, nil
+}
+
+// Attach runs the peer authentication protocol on the given host: it authenticates us to every peer we connect to,
+// handles authentication by remote peers, and disconnects peers which fail to authenticate.
+func (g *Gate) Attach(ctx context.Context, h host.Host, gk *ecdsa.PrivateKey, logger *zap.Logger) error {
+	auth, err := signPeerAuth(g.networkID, h.ID(), gk)
+	if err != nil {
+		return fmt.Errorf("failed to sign peer authentication: %w", err)
+	}
+	b, err := proto.Marshal(auth)
+	if err != nil {
+		return err
+	}
+
+	h.SetStreamHandler(authProtocol(g.networkID), func(s network.Stream) {
+		g.handleAuth(s, logger)
+	})
+
+	h.Network().Notify(&network.NotifyBundle{
+		ConnectedF: func(_ network.Network, c network.Conn) {
+			go g.onConnected(ctx, h, c.RemotePeer(), b, logger)
+		},
+	})
+
+	return nil
+}
+
+// onConnected sends our authentication to a newly connected peer and disconnects it unless
+// it authenticates itself within authTimeout.
+func (g *Gate) onConnected(ctx context.Context, h host.Host, p peer.ID, auth []byte, logger *zap.Logger) {
+	if err := g.sendAuth(ctx, h, p, auth); err != nil {
+		logger.Debug("failed to send peer authentication", zap.String("peer", p.String()), zap.Error(err))
+	}
+
+	if g.Admitted(p) {
+		return
+	}
+
+	timer := time.NewTimer(authTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		if g.Admitted(p) || h.Network().Connectedness(p) != network.Connected {
+			return
+		}
+
+		// We cannot verify guardians until our guardian set is initialized. Keep unauthenticated peers around
+		// until then (their messages are ignored) instead of disconnecting everyone during startup.
+		if g.gst.Get() == nil {
+			timer.Reset(authTimeout)
+			continue
+		}
+
+		if g.verifyPending(p, logger) {
+			return
+		}
+
+		logger.Info("disconnecting peer that failed to authenticate", zap.String("peer", p.String()))
+		p2pGateDenied.WithLabelValues("unauthenticated").Inc()
+		g.deny(p)
+		_ = h.Network().ClosePeer(p)
+		return
+	}
+}
+
+func (g *Gate) sendAuth(ctx context.Context, h host.Host, p peer.ID, auth []byte) error {
+	sCtx, cancel := context.WithTimeout(ctx, authTimeout)
+	defer cancel()
+
+	s, err := h.NewStream(sCtx, p, authProtocol(g.networkID))
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	_ = s.SetWriteDeadline(time.Now().Add(authTimeout))
+	_, err = s.Write(auth)
+	return err
+}
+
+// handleAuth handles an inbound PeerAuth message.
+func (g *Gate) handleAuth(s network.Stream, logger *zap.Logger) {
+	defer s.Close()
+	p := s.Conn().RemotePeer()
+
+	_ = s.SetReadDeadline(time.Now().Add(authTimeout))
+	b, err := ioutil.ReadAll(io.LimitReader(s, maxPeerAuthSize))
+	if err != nil {
+		logger.Debug("failed to read peer authentication", zap.String("peer", p.String()), zap.Error(err))
+		return
+	}
+
+	var m gossipv1.PeerAuth
+	if err := proto.Unmarshal(b, &m); err != nil {
+		logger.Info("received invalid peer authentication", zap.String("peer", p.String()), zap.Error(err))
+		return
+	}
+
+	g.mu.Lock()
+	g.pending[p] = &m
+	g.mu.Unlock()
+
+	if g.gst.Get() != nil {
+		g.verifyPending(p, logger)
+	}
+}
+
+// verifyPending verifies the pending PeerAuth message of the given peer, if any,
+// and returns whether the peer was authenticated.
+func (g *Gate) verifyPending(p peer.ID, logger *zap.Logger) bool {
+	g.mu.Lock()
+	m, ok := g.pending[p]
+	delete(g.pending, p)
+	g.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	addr, err := verifyPeerAuth(g.networkID, p, m, g.gst.Get())
+	if err != nil {
+		logger.Info("peer failed to authenticate", zap.String("peer", p.String()), zap.Error(err))
+		return false
+	}
+
+	g.mu.Lock()
+	g.authenticated[p] = addr
+	g.mu.Unlock()
+
+	logger.Info("peer authenticated", zap.String("peer", p.String()), zap.String("guardian_addr", addr.Hex()))
+	p2pPeersAuthenticated.Inc()
+	return true
+}
@@ -0,0 +1,76 @@
+package p2p
+
+import (
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+)
+
+func TestVerifyPeerAuth(t *testing.T) {
+	gk, addr := newTestGuardian(t)
+	otherGk, _ := newTestGuardian(t)
+	gs := &common.GuardianSet{Keys: []ethcommon.Address{addr}, Index: 1}
+	from := peer.ID("12D3KooWguardian")
+
+	m, err := signPeerAuth("/wormhole/private", from, gk)
+	require.NoError(t, err)
+
+	got, err := verifyPeerAuth("/wormhole/private", from, m, gs)
+	require.NoError(t, err)
+	assert.Equal(t, addr, got)
+
+	// Bound to the network ID and the sending peer.
+	_, err = verifyPeerAuth("/wormhole/other", from, m, gs)
+	assert.Error(t, err)
+	_, err = verifyPeerAuth("/wormhole/private", peer.ID("12D3KooWimpostor"), m, gs)
+	assert.Error(t, err)
+
+	_, err = verifyPeerAuth("/wormhole/private", from, m, nil)
+	assert.Error(t, err)
+
+	m, err = signPeerAuth("/wormhole/private", from, otherGk)
+	require.NoError(t, err)
+	_, err = verifyPeerAuth("/wormhole/private", from, m, gs)
+	assert.Error(t, err)
+}
+
+func TestGateAdmission(t *testing.T) {
+	gk, addr := newTestGuardian(t)
+	gst := common.NewGuardianSetState()
+
+	allowed := peer.ID("12D3KooWallowed")
+	guardian := peer.ID("12D3KooWguardian")
+	stranger := peer.ID("12D3KooWstranger")
+
+	// Allowlist only
+	g := NewGate("/wormhole/private", []peer.ID{allowed}, false, gst)
+	assert.True(t, g.InterceptSecured(0, allowed, nil))
+	assert.False(t, g.InterceptSecured(0, guardian, nil))
+	assert.True(t, g.Admitted(allowed))
+	assert.False(t, g.Admitted(guardian))
+
+	// Guardian authentication
+	g = NewGate("/wormhole/private", nil, true, gst)
+	assert.True(t, g.InterceptSecured(0, guardian, nil), "should be admitted provisionally")
+	assert.False(t, g.Admitted(guardian))
+
+	m, err := signPeerAuth("/wormhole/private", guardian, gk)
+	require.NoError(t, err)
+	g.pending[guardian] = m
+
+	// Pending authentication is verified once the guardian set is known.
+	gst.Set(&common.GuardianSet{Keys: []ethcommon.Address{addr}, Index: 1})
+	assert.True(t, g.verifyPending(guardian, zap.NewNop()))
+	assert.True(t, g.Admitted(guardian))
+
+	assert.False(t, g.verifyPending(stranger, zap.NewNop()))
+	g.deny(stranger)
+	assert.False(t, g.InterceptSecured(0, stranger, nil))
+	assert.False(t, g.InterceptPeerDial(stranger))
+}
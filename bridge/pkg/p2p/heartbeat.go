@@ -0,0 +1,84 @@
+package p2p
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+)
+
+// heartbeatDigest returns the digest signed by a guardian's SignedHeartbeat. Including the sender's peer ID
+// prevents other nodes from replaying the heartbeat as their own.
+func heartbeatDigest(b []byte, from peer.ID) []byte {
+	return ethcrypto.Keccak256(b, []byte(from))
+}
+
+// signHeartbeat serializes and signs a heartbeat sent by the given peer using the guardian key.
+func signHeartbeat(hb *gossipv1.Heartbeat, from peer.ID, gk *ecdsa.PrivateKey) (*gossipv1.SignedHeartbeat, error) {
+	b, err := proto.Marshal(hb)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := ethcrypto.Sign(heartbeatDigest(b, from), gk)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gossipv1.SignedHeartbeat{
+		Heartbeat:    b,
+		Signature:    sig,
+		GuardianAddr: ethcrypto.PubkeyToAddress(gk.PublicKey).Bytes(),
+	}, nil
+}
+
+// decodeSignedHeartbeat deserializes the heartbeat within a SignedHeartbeat, without verifying it.
+func decodeSignedHeartbeat(s *gossipv1.SignedHeartbeat) (*gossipv1.Heartbeat, error) {
+	var hb gossipv1.Heartbeat
+	if err := proto.Unmarshal(s.Heartbeat, &hb); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal heartbeat: %w", err)
+	}
+	return &hb, nil
+}
+
+// verifySignedHeartbeat verifies that a SignedHeartbeat authored by the given peer was signed by a member of
+// the given guardian set, and that the heartbeat's self-reported guardian address matches the signer.
+func verifySignedHeartbeat(from peer.ID, s *gossipv1.SignedHeartbeat, gs *common.GuardianSet) (*gossipv1.Heartbeat, error) {
+	if gs == nil {
+		return nil, errors.New("guardian set is not initialized")
+	}
+
+	hb, err := decodeSignedHeartbeat(s)
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := ethcrypto.Ecrecover(heartbeatDigest(s.Heartbeat, from), s.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover public key: %w", err)
+	}
+
+	signer := ethcommon.BytesToAddress(ethcrypto.Keccak256(pk[1:])[12:])
+	if !bytes.Equal(signer.Bytes(), s.GuardianAddr) {
+		return nil, fmt.Errorf("signer %s does not match guardian address %s",
+			signer.Hex(), ethcommon.BytesToAddress(s.GuardianAddr).Hex())
+	}
+
+	if _, ok := gs.KeyIndex(signer); !ok {
+		return nil, fmt.Errorf("signer %s is not in guardian set %d", signer.Hex(), gs.Index)
+	}
+
+	if hb.GuardianAddr != signer.Hex() {
+		return nil, fmt.Errorf("heartbeat guardian address %s does not match signer %s", hb.GuardianAddr, signer.Hex())
+	}
+
+	return hb, nil
+}
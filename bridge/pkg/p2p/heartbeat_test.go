@@ -0,0 +1,75 @@
+package p2p
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+)
+
+func newTestGuardian(t *testing.T) (*ecdsa.PrivateKey, ethcommon.Address) {
+	t.Helper()
+	gk, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+	return gk, ethcrypto.PubkeyToAddress(gk.PublicKey)
+}
+
+func TestVerifySignedHeartbeat(t *testing.T) {
+	gk, addr := newTestGuardian(t)
+	otherGk, otherAddr := newTestGuardian(t)
+
+	gs := &common.GuardianSet{Keys: []ethcommon.Address{addr}, Index: 1}
+	from := peer.ID("12D3KooWsender")
+
+	hb := &gossipv1.Heartbeat{NodeName: "guardian-0", Counter: 42, GuardianAddr: addr.Hex()}
+
+	s, err := signHeartbeat(hb, from, gk)
+	require.NoError(t, err)
+
+	got, err := verifySignedHeartbeat(from, s, gs)
+	require.NoError(t, err)
+	assert.Equal(t, "guardian-0", got.NodeName)
+	assert.Equal(t, int64(42), got.Counter)
+
+	t.Run("uninitialized guardian set", func(t *testing.T) {
+		_, err := verifySignedHeartbeat(from, s, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("replayed by another peer", func(t *testing.T) {
+		_, err := verifySignedHeartbeat(peer.ID("12D3KooWimpostor"), s, gs)
+		assert.Error(t, err)
+	})
+
+	t.Run("tampered heartbeat", func(t *testing.T) {
+		tampered := &gossipv1.SignedHeartbeat{
+			Heartbeat:    append([]byte{}, s.Heartbeat...),
+			Signature:    s.Signature,
+			GuardianAddr: s.GuardianAddr,
+		}
+		tampered.Heartbeat[len(tampered.Heartbeat)-1] ^= 0xff
+		_, err := verifySignedHeartbeat(from, tampered, gs)
+		assert.Error(t, err)
+	})
+
+	t.Run("signer not in guardian set", func(t *testing.T) {
+		s, err := signHeartbeat(&gossipv1.Heartbeat{GuardianAddr: otherAddr.Hex()}, from, otherGk)
+		require.NoError(t, err)
+		_, err = verifySignedHeartbeat(from, s, gs)
+		assert.Error(t, err)
+	})
+
+	t.Run("self-reported address mismatch", func(t *testing.T) {
+		s, err := signHeartbeat(&gossipv1.Heartbeat{GuardianAddr: otherAddr.Hex()}, from, gk)
+		require.NoError(t, err)
+		_, err = verifySignedHeartbeat(from, s, gs)
+		assert.Error(t, err)
+	})
+}
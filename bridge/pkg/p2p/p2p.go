@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"fmt"
 	"github.com/certusone/wormhole/bridge/pkg/version"
 	"github.com/prometheus/client_golang/prometheus"
@@ -11,21 +12,24 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiformats/go-multiaddr"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/libp2p/go-libp2p"
-	connmgr "github.com/libp2p/go-libp2p-connmgr"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	"github.com/libp2p/go-libp2p-core/pnet"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	"github.com/libp2p/go-libp2p-core/routing"
 	dht "github.com/libp2p/go-libp2p-kad-dht"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
-	libp2pquic "github.com/libp2p/go-libp2p-quic-transport"
-	libp2ptls "github.com/libp2p/go-libp2p-tls"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/certusone/wormhole/bridge/pkg/common"
 	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+	publicrpcv1 "github.com/certusone/wormhole/bridge/pkg/proto/publicrpc/v1"
 	"github.com/certusone/wormhole/bridge/pkg/publicrpc"
+	"github.com/certusone/wormhole/bridge/pkg/readiness"
 	"github.com/certusone/wormhole/bridge/pkg/supervisor"
 )
 
@@ -35,16 +39,16 @@ var (
 			Name: "wormhole_p2p_heartbeats_sent_total",
 			Help: "Total number of p2p heartbeats sent",
 		})
-	p2pMessagesSent = prometheus.NewCounter(
+	p2pMessagesSent = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "wormhole_p2p_broadcast_messages_sent_total",
-			Help: "Total number of p2p pubsub broadcast messages sent",
-		})
+			Help: "Total number of p2p pubsub broadcast messages sent, grouped by topic",
+		}, []string{"topic"})
 	p2pMessagesReceived = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "wormhole_p2p_broadcast_messages_received_total",
-			Help: "Total number of p2p pubsub broadcast messages received",
-		}, []string{"type"})
+			Help: "Total number of p2p pubsub broadcast messages received, grouped by topic and message type",
+		}, []string{"topic", "type"})
 )
 
 func init() {
@@ -53,237 +57,518 @@ func init() {
 	prometheus.MustRegister(p2pMessagesReceived)
 }
 
-func Run(obsvC chan *gossipv1.SignedObservation,
+// HostConfig configures the node's libp2p host.
+type HostConfig struct {
+	// Port is the port used by the default listen addresses.
+	Port uint
+	// NetworkID is the p2p network identifier, used as DHT protocol prefix.
+	NetworkID string
+
+	// Transports lists the enabled transports (TransportQUIC, TransportTCP).
+	// Defaults to QUIC, or TCP in private networks using a PSK.
+	Transports []string
+	// Security lists the security protocols for the TCP transport (SecurityTLS, SecurityNoise) in order of
+	// preference. Defaults to TLS.
+	Security []string
+	// ListenAddrs are the multiaddrs to listen on. Defaults to wildcard addresses on Port for each transport.
+	ListenAddrs []string
+	// AnnounceAddrs, if set, are advertised to peers instead of the listen addresses.
+	AnnounceAddrs []string
+	// ConnManager configures the connection limits. Defaults to DefaultConnManagerConfig.
+	ConnManager ConnManagerConfig
+
+	// Gate restricts connections to admitted peers in private network mode. Nil in public mode.
+	Gate *Gate
+	// PSK, if set, restricts the host to a libp2p private network. Since QUIC does not support
+	// private networks, the host must use the TCP transport.
+	PSK pnet.PSK
+}
+
+// NewHost creates the node's libp2p host.
+//
+// libp2p cannot be cleanly restarted (https://github.com/libp2p/go-libp2p/issues/992), so the host is created once
+// and outlives the supervised p2p runnables. It is shut down when ctx is cancelled or the host is closed.
+func NewHost(ctx context.Context, priv crypto.PrivKey, cfg HostConfig) (host.Host, error) {
+	tOpts, err := transportOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []libp2p.Option{
+		// Use the keypair we generated
+		libp2p.Identity(priv),
+
+		// Let this host use the DHT to find other hosts
+		libp2p.Routing(func(h host.Host) (routing.PeerRouting, error) {
+			idht, err := dht.New(ctx, h, dht.Mode(dht.ModeServer),
+				// TODO(leo): This intentionally makes us incompatible with the global IPFS DHT
+				dht.ProtocolPrefix(protocol.ID("/"+cfg.NetworkID)),
+			)
+			return idht, err
+		}),
+	}
+	opts = append(opts, tOpts...)
+
+	if cfg.PSK != nil {
+		opts = append(opts, libp2p.PrivateNetwork(cfg.PSK))
+	}
+
+	if cfg.Gate != nil {
+		opts = append(opts, libp2p.ConnectionGater(cfg.Gate))
+	}
+
+	return libp2p.New(ctx, opts...)
+}
+
+// Node runs the gossip protocol on top of a long-lived libp2p host.
+//
+// Its runnables (bootstrap, heartbeat, publish and one subscriber per topic) are independently restartable by the
+// supervisor, such that a transient gossip failure does not affect the rest of the guardian.
+type Node struct {
+	h host.Host
+	// topics maps topic names (like TopicHeartbeat) to joined pubsub topics
+	topics map[string]*pubsub.Topic
+	// legacyBroadcast specifies whether to publish observations and heartbeats on and receive from TopicLegacyBroadcast,
+	// for compatibility with nodes predating protocol version 1.
+	legacyBroadcast bool
+	// versions tracks peers' protocol versions
+	versions *peerVersions
+	// guardians tracks which peers are run by a guardian
+	guardians *guardianPeers
+	// gate is the private network gate, or nil in public mode
+	gate *Gate
+
+	// obsvC is a channel of inbound decoded observations
+	obsvC chan *gossipv1.SignedObservation
+	// sendC is a channel of outbound serialized GossipMessages containing observations
+	sendC chan []byte
+
+	rawHeartbeatListeners *publicrpc.PublicRawHeartbeatConnections
+
+	// gk is the node's guardian private key, used to sign heartbeats
+	gk *ecdsa.PrivateKey
+	// gst is the current guardian set, used to verify heartbeats
+	gst *common.GuardianSetState
+
+	bootstrapPeers string
+	peerstorePath  string
+	minPeers       int
+	nodeName       string
+}
+
+// New joins the gossip topics for the given network on an existing libp2p host.
+// The pubsub router shares the host's lifetime and is shut down when ctx is cancelled. pubsubOpts are passed
+// to the gossipsub router in addition to our own options.
+func New(ctx context.Context,
+	h host.Host,
+	networkID string,
+	legacyBroadcast bool,
+	gate *Gate,
+	obsvC chan *gossipv1.SignedObservation,
 	sendC chan []byte,
 	rawHeartbeatListeners *publicrpc.PublicRawHeartbeatConnections,
-	priv crypto.PrivKey,
-	port uint,
-	networkID string,
+	gk *ecdsa.PrivateKey,
+	gst *common.GuardianSetState,
 	bootstrapPeers string,
+	peerstorePath string,
+	minPeers int,
 	nodeName string,
-	rootCtxCancel context.CancelFunc) func(ctx context.Context) error {
-
-	return func(ctx context.Context) (re error) {
-		logger := supervisor.Logger(ctx)
-
-		h, err := libp2p.New(ctx,
-			// Use the keypair we generated
-			libp2p.Identity(priv),
-
-			// Multiple listen addresses
-			libp2p.ListenAddrStrings(
-				// Listen on QUIC only.
-				// https://github.com/libp2p/go-libp2p/issues/688
-				fmt.Sprintf("/ip4/0.0.0.0/udp/%d/quic", port),
-				fmt.Sprintf("/ip6/::/udp/%d/quic", port),
-			),
-
-			// Enable TLS security as the only security protocol.
-			libp2p.Security(libp2ptls.ID, libp2ptls.New),
-
-			// Enable QUIC transport as the only transport.
-			libp2p.Transport(libp2pquic.NewTransport),
-
-			// Let's prevent our peer from having too many
-			// connections by attaching a connection manager.
-			libp2p.ConnectionManager(connmgr.NewConnManager(
-				100,         // Lowwater
-				400,         // HighWater,
-				time.Minute, // GracePeriod
-			)),
-
-			// Let this host use the DHT to find other hosts
-			libp2p.Routing(func(h host.Host) (routing.PeerRouting, error) {
-				// TODO(leo): Persistent data store (i.e. address book)
-				idht, err := dht.New(ctx, h, dht.Mode(dht.ModeServer),
-					// TODO(leo): This intentionally makes us incompatible with the global IPFS DHT
-					dht.ProtocolPrefix(protocol.ID("/"+networkID)),
-				)
-				return idht, err
-			}),
-		)
+	pubsubOpts ...pubsub.Option) (*Node, error) {
+
+	names := []string{TopicHeartbeat, TopicObservation}
+	if legacyBroadcast {
+		names = append(names, TopicLegacyBroadcast)
+	}
+
+	fullNames := make([]string, len(names))
+	for i, name := range names {
+		fullNames[i] = topicName(networkID, name)
+	}
+
+	ps, err := pubsub.NewGossipSub(ctx, h,
+		append([]pubsub.Option{pubsub.WithPeerScore(peerScoreParams(fullNames))}, pubsubOpts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pubsub router: %w", err)
+	}
 
+	topics := make(map[string]*pubsub.Topic, len(names))
+	for i, name := range names {
+		if err := ps.RegisterTopicValidator(fullNames[i], newValidator(name, h.ID(), gst, gate)); err != nil {
+			return nil, fmt.Errorf("failed to register validator for topic %s: %w", fullNames[i], err)
+		}
+
+		th, err := ps.Join(fullNames[i])
 		if err != nil {
-			panic(err)
+			return nil, fmt.Errorf("failed to join topic %s: %w", fullNames[i], err)
 		}
+		topics[name] = th
+	}
 
-		defer func() {
-			// TODO: libp2p cannot be cleanly restarted (https://github.com/libp2p/go-libp2p/issues/992)
-			logger.Error("p2p routine has exited, cancelling root context...", zap.Error(re))
-			rootCtxCancel()
-		}()
+	return &Node{
+		h:                     h,
+		topics:                topics,
+		legacyBroadcast:       legacyBroadcast,
+		versions:              newPeerVersions(),
+		guardians:             newGuardianPeers(),
+		gate:                  gate,
+		obsvC:                 obsvC,
+		sendC:                 sendC,
+		rawHeartbeatListeners: rawHeartbeatListeners,
+		gk:                    gk,
+		gst:                   gst,
+		bootstrapPeers:        bootstrapPeers,
+		peerstorePath:         peerstorePath,
+		minPeers:              minPeers,
+		nodeName:              nodeName,
+	}, nil
+}
 
-		logger.Info("Connecting to bootstrap peers", zap.String("bootstrap_peers", bootstrapPeers))
+// TopicPeers returns the peers we know to be subscribed to the given topic (like TopicObservation).
+func (n *Node) TopicPeers(topic string) []peer.ID {
+	t, ok := n.topics[topic]
+	if !ok {
+		return nil
+	}
+	return t.ListPeers()
+}
 
-		topic := fmt.Sprintf("%s/%s", networkID, "broadcast")
+// Run starts the node's runnables as children and returns once ctx is cancelled.
+func (n *Node) Run(ctx context.Context) error {
+	logger := supervisor.Logger(ctx)
 
-		logger.Info("Subscribing pubsub topic", zap.String("topic", topic))
-		ps, err := pubsub.NewGossipSub(ctx, h)
-		if err != nil {
-			panic(err)
+	// Started individually rather than as a group, such that they are restarted independently.
+	if err := supervisor.Run(ctx, "bootstrap", n.runBootstrap); err != nil {
+		return err
+	}
+	if err := supervisor.Run(ctx, "heartbeat", n.runHeartbeat); err != nil {
+		return err
+	}
+	if err := supervisor.Run(ctx, "publish", n.runPublish); err != nil {
+		return err
+	}
+	for name := range n.topics {
+		if err := supervisor.Run(ctx, name+"sub", n.subscriber(name)); err != nil {
+			return err
 		}
+	}
+
+	logger.Info("Node has been started", zap.String("peer_id", n.h.ID().String()),
+		zap.String("addrs", fmt.Sprintf("%v", n.h.Addrs())))
+
+	supervisor.Signal(ctx, supervisor.SignalHealthy)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// runBootstrap connects to the bootstrap and persisted peers and keeps the node connected to enough peers.
+func (n *Node) runBootstrap(ctx context.Context) error {
+	logger := supervisor.Logger(ctx)
+	h := n.h
+
+	logger.Info("Connecting to bootstrap peers", zap.String("bootstrap_peers", n.bootstrapPeers))
 
-		th, err := ps.Join(topic)
+	// Seed the address book with our bootstrap nodes and the persisted peerstore. The connector dials
+	// all peers in the address book, so both are treated as bootstrap candidates.
+
+	// Are we a bootstrap node? If so, it's okay to not have any peers.
+	bootstrapNode := false
+
+	for _, addr := range strings.Split(n.bootstrapPeers, ",") {
+		if addr == "" {
+			continue
+		}
+		ma, err := multiaddr.NewMultiaddr(addr)
 		if err != nil {
-			return fmt.Errorf("failed to join topic: %w", err)
+			logger.Error("Invalid bootstrap address", zap.String("peer", addr), zap.Error(err))
+			continue
 		}
-
-		sub, err := th.Subscribe()
+		pi, err := peer.AddrInfoFromP2pAddr(ma)
 		if err != nil {
-			return fmt.Errorf("failed to subscribe topic: %w", err)
+			logger.Error("Invalid bootstrap address", zap.String("peer", addr), zap.Error(err))
+			continue
 		}
 
-		// Add our own bootstrap nodes
+		if pi.ID == h.ID() {
+			logger.Info("We're a bootstrap node")
+			bootstrapNode = true
+			continue
+		}
 
-		// Count number of successful connection attempts. If we fail to connect to any bootstrap peer, kill
-		// the service and have supervisor retry it.
-		successes := 0
-		// Are we a bootstrap node? If so, it's okay to not have any peers.
-		bootstrapNode := false
+		h.Peerstore().AddAddrs(pi.ID, pi.Addrs, peerstore.PermanentAddrTTL)
+	}
 
-		for _, addr := range strings.Split(bootstrapPeers, ",") {
-			if addr == "" {
+	if n.peerstorePath != "" {
+		persisted, err := loadPeerstore(n.peerstorePath)
+		if err != nil {
+			// Not fatal - the peerstore is merely an optimization.
+			logger.Error("Failed to load peerstore", zap.String("path", n.peerstorePath), zap.Error(err))
+		}
+		for _, pi := range persisted {
+			if pi.ID == h.ID() {
 				continue
 			}
-			ma, err := multiaddr.NewMultiaddr(addr)
-			if err != nil {
-				logger.Error("Invalid bootstrap address", zap.String("peer", addr), zap.Error(err))
-				continue
+			h.Peerstore().AddAddrs(pi.ID, pi.Addrs, peerstore.AddressTTL)
+		}
+		logger.Info("Loaded persisted peers", zap.String("path", n.peerstorePath), zap.Int("num", len(persisted)))
+	}
+
+	c := newConnector(h, logger, n.guardians, n.gst, n.minPeers, n.peerstorePath)
+
+	// Failing to connect to any peer is not fatal - the connector keeps retrying in the background.
+	successes := c.dialCandidates(ctx)
+	if successes == 0 && !bootstrapNode {
+		logger.Warn("Failed to connect to any bootstrap peer, retrying in the background")
+	} else {
+		logger.Info("Connected to initial peers", zap.Int("num", successes))
+	}
+
+	supervisor.Signal(ctx, supervisor.SignalHealthy)
+	c.run(ctx)
+	return ctx.Err()
+}
+
+// runHeartbeat periodically broadcasts a signed heartbeat.
+func (n *Node) runHeartbeat(ctx context.Context) error {
+	logger := supervisor.Logger(ctx)
+
+	ctr := int64(0)
+	tick := time.NewTicker(15 * time.Second)
+	defer tick.Stop()
+
+	supervisor.Signal(ctx, supervisor.SignalHealthy)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick.C:
+			DefaultRegistry.mu.Lock()
+			networks := make([]*gossipv1.Heartbeat_Network, 0, len(DefaultRegistry.networkStats))
+			for _, v := range DefaultRegistry.networkStats {
+				networks = append(networks, v)
 			}
-			pi, err := peer.AddrInfoFromP2pAddr(ma)
+
+			heartbeat := &gossipv1.Heartbeat{
+				NodeName:     n.nodeName,
+				Counter:      ctr,
+				Timestamp:    time.Now().UnixNano(),
+				Networks:     networks,
+				Version:      version.Version(),
+				GuardianAddr: DefaultRegistry.guardianAddress,
+				P2PAddrs:     hostAddrs(n.h),
+			}
+			DefaultRegistry.mu.Unlock()
+
+			s, err := signHeartbeat(heartbeat, n.h.ID(), n.gk)
 			if err != nil {
-				logger.Error("Invalid bootstrap address", zap.String("peer", addr), zap.Error(err))
-				continue
+				panic(err)
 			}
 
-			if pi.ID == h.ID() {
-				logger.Info("We're a bootstrap node")
-				bootstrapNode = true
-				continue
+			// Our own heartbeat goes through the same verification as everyone else's - it fails if our guardian
+			// key is not part of the current guardian set.
+			_, verifyErr := verifySignedHeartbeat(n.h.ID(), s, n.gst.Get())
+			n.rawHeartbeatListeners.PublishHeartbeat(&publicrpcv1.HeartbeatEvent{
+				Heartbeat: heartbeat,
+				Verified:  verifyErr == nil,
+				P2PNodeId: n.h.ID().String(),
+			})
+
+			msg := gossipv1.GossipMessage{
+				ProtocolVersion: common.ProtocolVersion,
+				Message: &gossipv1.GossipMessage_SignedHeartbeat{
+					SignedHeartbeat: s,
+				},
 			}
 
-			if err = h.Connect(ctx, *pi); err != nil {
-				logger.Error("Failed to connect to bootstrap peer", zap.String("peer", addr), zap.Error(err))
-			} else {
-				successes += 1
+			b, err := proto.Marshal(&msg)
+			if err != nil {
+				panic(err)
 			}
-		}
 
-		// TODO: continually reconnect to bootstrap nodes?
-		if successes == 0 && !bootstrapNode {
-			return fmt.Errorf("failed to connect to any bootstrap peer")
-		} else {
-			logger.Info("Connected to bootstrap peers", zap.Int("num", successes))
-		}
+			err = n.topics[TopicHeartbeat].Publish(ctx, b)
+			if err != nil {
+				logger.Warn("failed to publish heartbeat message", zap.Error(err))
+			} else {
+				readiness.ReportProgress(common.LivenessP2PHeartbeat)
+			}
+			p2pMessagesSent.WithLabelValues(TopicHeartbeat).Inc()
+
+			if n.legacyBroadcast {
+				// Nodes predating signed heartbeats only listen for unsigned ones on the legacy topic.
+				// TODO: remove once all guardians have upgraded.
+				legacy, err := proto.Marshal(&gossipv1.GossipMessage{
+					ProtocolVersion: common.ProtocolVersion,
+					Message:         &gossipv1.GossipMessage_Heartbeat{Heartbeat: heartbeat},
+				})
+				if err != nil {
+					panic(err)
+				}
 
-		logger.Info("Node has been started", zap.String("peer_id", h.ID().String()),
-			zap.String("addrs", fmt.Sprintf("%v", h.Addrs())))
-
-		go func() {
-			ctr := int64(0)
-			tick := time.NewTicker(15 * time.Second)
-			defer tick.Stop()
-
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case <-tick.C:
-					DefaultRegistry.mu.Lock()
-					networks := make([]*gossipv1.Heartbeat_Network, 0, len(DefaultRegistry.networkStats))
-					for _, v := range DefaultRegistry.networkStats {
-						networks = append(networks, v)
-					}
-
-					msg := gossipv1.GossipMessage{Message: &gossipv1.GossipMessage_Heartbeat{
-						Heartbeat: &gossipv1.Heartbeat{
-							NodeName:     nodeName,
-							Counter:      ctr,
-							Timestamp:    time.Now().UnixNano(),
-							Networks:     networks,
-							Version:      version.Version(),
-							GuardianAddr: DefaultRegistry.guardianAddress,
-						}}}
-
-					rawHeartbeatListeners.PublishHeartbeat(msg.GetHeartbeat())
-
-					b, err := proto.Marshal(&msg)
-					if err != nil {
-						panic(err)
-					}
-					DefaultRegistry.mu.Unlock()
-
-					err = th.Publish(ctx, b)
-					if err != nil {
-						logger.Warn("failed to publish heartbeat message", zap.Error(err))
-					}
-
-					p2pHeartbeatsSent.Inc()
-					ctr += 1
+				if err := n.topics[TopicLegacyBroadcast].Publish(ctx, legacy); err != nil {
+					logger.Warn("failed to publish legacy heartbeat message", zap.Error(err))
 				}
+				p2pMessagesSent.WithLabelValues(TopicLegacyBroadcast).Inc()
 			}
-		}()
-
-		go func() {
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case msg := <-sendC:
-					err := th.Publish(ctx, msg)
-					p2pMessagesSent.Inc()
-					if err != nil {
-						logger.Error("failed to publish message from queue", zap.Error(err))
-					}
+
+			p2pHeartbeatsSent.Inc()
+			ctr += 1
+		}
+	}
+}
+
+// hostAddrs returns the string representation of the addresses the host advertises to its peers.
+func hostAddrs(h host.Host) []string {
+	addrs := h.Addrs()
+	s := make([]string, len(addrs))
+	for i, a := range addrs {
+		s[i] = a.String()
+	}
+	return s
+}
+
+// runPublish broadcasts outbound observations from sendC.
+func (n *Node) runPublish(ctx context.Context) error {
+	logger := supervisor.Logger(ctx)
+
+	topics := []string{TopicObservation}
+	if n.legacyBroadcast {
+		// Nodes predating protocol version 1 only listen on the legacy topic.
+		topics = append(topics, TopicLegacyBroadcast)
+	}
+
+	supervisor.Signal(ctx, supervisor.SignalHealthy)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-n.sendC:
+			for _, t := range topics {
+				err := n.topics[t].Publish(ctx, msg)
+				p2pMessagesSent.WithLabelValues(t).Inc()
+				if err != nil {
+					logger.Error("failed to publish message from queue", zap.String("topic", t), zap.Error(err))
 				}
 			}
-		}()
+		}
+	}
+}
 
-		for {
-			envelope, err := sub.Next(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to receive pubsub message: %w", err)
-			}
+// subscriber returns a runnable that subscribes to the given topic and dispatches inbound messages.
+// Each topic has its own subscription (and therefore queue), such that topics do not compete with each other.
+func (n *Node) subscriber(topic string) supervisor.Runnable {
+	return func(ctx context.Context) error {
+		return n.runSubscribe(ctx, topic)
+	}
+}
 
-			var msg gossipv1.GossipMessage
-			err = proto.Unmarshal(envelope.Data, &msg)
-			if err != nil {
-				logger.Info("received invalid message",
-					zap.String("data", string(envelope.Data)),
-					zap.String("from", envelope.GetFrom().String()))
-				p2pMessagesReceived.WithLabelValues("invalid").Inc()
-				continue
-			}
+func (n *Node) runSubscribe(ctx context.Context, topic string) error {
+	logger := supervisor.Logger(ctx)
+	h := n.h
 
-			if envelope.GetFrom() == h.ID() {
-				logger.Debug("received message from ourselves, ignoring",
-					zap.Any("payload", msg.Message))
-				p2pMessagesReceived.WithLabelValues("loopback").Inc()
-				continue
-			}
+	sub, err := n.topics[topic].Subscribe()
+	if err != nil {
+		return fmt.Errorf("failed to subscribe topic: %w", err)
+	}
+	defer sub.Cancel()
 
-			logger.Debug("received message",
-				zap.Any("payload", msg.Message),
-				zap.Binary("raw", envelope.Data),
+	supervisor.Signal(ctx, supervisor.SignalHealthy)
+
+	for {
+		envelope, err := sub.Next(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to receive pubsub message: %w", err)
+		}
+
+		var msg gossipv1.GossipMessage
+		err = proto.Unmarshal(envelope.Data, &msg)
+		if err != nil {
+			logger.Info("received invalid message",
+				zap.String("data", string(envelope.Data)),
 				zap.String("from", envelope.GetFrom().String()))
+			p2pMessagesReceived.WithLabelValues(topic, "invalid").Inc()
+			continue
+		}
 
-			switch m := msg.Message.(type) {
-			case *gossipv1.GossipMessage_Heartbeat:
-				logger.Debug("heartbeat received",
-					zap.Any("value", m.Heartbeat),
+		if envelope.GetFrom() == h.ID() {
+			logger.Debug("received message from ourselves, ignoring",
+				zap.Any("payload", msg.Message))
+			p2pMessagesReceived.WithLabelValues(topic, "loopback").Inc()
+			continue
+		}
+
+		n.versions.observe(logger, envelope.GetFrom(), msg.ProtocolVersion)
+
+		// Versioned nodes publish to the legacy topic for the benefit of older nodes only,
+		// and we receive the same message on its dedicated topic.
+		if topic == TopicLegacyBroadcast && msg.ProtocolVersion >= 1 {
+			p2pMessagesReceived.WithLabelValues(topic, "duplicate").Inc()
+			continue
+		}
+
+		logger.Debug("received message",
+			zap.Any("payload", msg.Message),
+			zap.Binary("raw", envelope.Data),
+			zap.String("from", envelope.GetFrom().String()))
+
+		switch m := msg.Message.(type) {
+		case *gossipv1.GossipMessage_Heartbeat:
+			// Unsigned heartbeat sent by a node running outdated software.
+			logger.Debug("legacy heartbeat received",
+				zap.Any("value", m.Heartbeat),
+				zap.String("from", envelope.GetFrom().String()))
+			n.rawHeartbeatListeners.PublishHeartbeat(&publicrpcv1.HeartbeatEvent{
+				Heartbeat: m.Heartbeat,
+				Verified:  false,
+				P2PNodeId: envelope.GetFrom().String(),
+			})
+			p2pMessagesReceived.WithLabelValues(topic, "heartbeat_legacy").Inc()
+		case *gossipv1.GossipMessage_SignedHeartbeat:
+			verified := true
+			hb, verifyErr := verifySignedHeartbeat(envelope.GetFrom(), m.SignedHeartbeat, n.gst.Get())
+			if verifyErr != nil {
+				verified = false
+
+				// Heartbeats are informational - we still pass on heartbeats that fail verification
+				// (flagged as unverified) as long as they can be decoded.
+				hb, err = decodeSignedHeartbeat(m.SignedHeartbeat)
+				if err != nil {
+					logger.Info("received undecodable signed heartbeat",
+						zap.Error(err),
+						zap.String("from", envelope.GetFrom().String()))
+					p2pMessagesReceived.WithLabelValues(topic, "heartbeat_invalid").Inc()
+					continue
+				}
+
+				logger.Warn("received heartbeat that failed verification",
+					zap.Error(verifyErr),
+					zap.Any("value", hb),
 					zap.String("from", envelope.GetFrom().String()))
-				rawHeartbeatListeners.PublishHeartbeat(msg.GetHeartbeat())
-				p2pMessagesReceived.WithLabelValues("heartbeat").Inc()
-			case *gossipv1.GossipMessage_SignedObservation:
-				obsvC <- m.SignedObservation
-				p2pMessagesReceived.WithLabelValues("observation").Inc()
-			default:
-				p2pMessagesReceived.WithLabelValues("unknown").Inc()
-				logger.Warn("received unknown message type (running outdated software?)",
-					zap.Any("payload", msg.Message),
-					zap.Binary("raw", envelope.Data),
+				p2pMessagesReceived.WithLabelValues(topic, "heartbeat_unverified").Inc()
+			} else {
+				n.guardians.observe(envelope.GetFrom(), ethcommon.HexToAddress(hb.GuardianAddr))
+				logger.Debug("heartbeat received",
+					zap.Any("value", hb),
 					zap.String("from", envelope.GetFrom().String()))
+				p2pMessagesReceived.WithLabelValues(topic, "heartbeat").Inc()
 			}
+
+			n.rawHeartbeatListeners.PublishHeartbeat(&publicrpcv1.HeartbeatEvent{
+				Heartbeat: hb,
+				Verified:  verified,
+				P2PNodeId: envelope.GetFrom().String(),
+			})
+		case *gossipv1.GossipMessage_SignedObservation:
+			n.obsvC <- m.SignedObservation
+			p2pMessagesReceived.WithLabelValues(topic, "observation").Inc()
+		default:
+			p2pMessagesReceived.WithLabelValues(topic, "unknown").Inc()
+			logger.Warn("received unknown message type",
+				zap.Uint32("version", msg.ProtocolVersion),
+				zap.String("compatibility", protocolCompatibility(msg.ProtocolVersion)),
+				zap.Any("payload", msg.Message),
+				zap.Binary("raw", envelope.Data),
+				zap.String("from", envelope.GetFrom().String()))
 		}
 	}
 }
@@ -0,0 +1,78 @@
+package p2p
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// The persistent peerstore is a simple address book of peers we've been connected to, written to disk periodically
+// and reloaded at startup. Its entries are used as additional bootstrap candidates, such that a node can rejoin the
+// network even if all bootstrap peers are unreachable.
+
+type peerstoreFile struct {
+	Peers []peer.AddrInfo `json:"peers"`
+}
+
+// loadPeerstore reads the peers persisted at the given path. A missing file is not an error and returns no peers.
+func loadPeerstore(path string) ([]peer.AddrInfo, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read peerstore: %w", err)
+	}
+
+	var f peerstoreFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse peerstore: %w", err)
+	}
+
+	return f.Peers, nil
+}
+
+// savePeerstore atomically replaces the peerstore at the given path.
+func savePeerstore(path string, peers []peer.AddrInfo) error {
+	b, err := json.MarshalIndent(&peerstoreFile{Peers: peers}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write peerstore: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write peerstore: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace peerstore: %w", err)
+	}
+
+	return nil
+}
+
+// knownPeers returns all peers in the host's in-memory address book which have at least one known address,
+// excluding the host itself.
+func knownPeers(h host.Host) []peer.AddrInfo {
+	var peers []peer.AddrInfo
+	for _, id := range h.Peerstore().PeersWithAddrs() {
+		if id == h.ID() {
+			continue
+		}
+		peers = append(peers, h.Peerstore().PeerInfo(id))
+	}
+	return peers
+}
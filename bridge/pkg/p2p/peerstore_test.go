@@ -0,0 +1,33 @@
+package p2p
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerstoreRoundtrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peerstore.json")
+
+	// A missing peerstore is not an error.
+	peers, err := loadPeerstore(path)
+	require.NoError(t, err)
+	assert.Empty(t, peers)
+
+	ma, err := multiaddr.NewMultiaddr("/ip4/10.0.0.1/udp/8999/quic/p2p/12D3KooWL3XJ9EMCyZvmmGXL2LMiVBtrVa2BuESsJiXkSj7333Jw")
+	require.NoError(t, err)
+	pi, err := peer.AddrInfoFromP2pAddr(ma)
+	require.NoError(t, err)
+
+	require.NoError(t, savePeerstore(path, []peer.AddrInfo{*pi}))
+
+	peers, err = loadPeerstore(path)
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, pi.ID, peers[0].ID)
+	assert.Equal(t, pi.Addrs[0].String(), peers[0].Addrs[0].String())
+}
@@ -1,6 +1,7 @@
 package p2p
 
 import (
+	"github.com/certusone/wormhole/bridge/pkg/common"
 	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
 	"github.com/certusone/wormhole/bridge/pkg/vaa"
 	"sync"
@@ -44,3 +45,18 @@ func (r *registry) SetNetworkStats(chain vaa.ChainID, data *gossipv1.Heartbeat_N
 	r.networkStats[chain] = data
 	r.mu.Unlock()
 }
+
+// chainStats reports a single chain's network status to a registry.
+type chainStats struct {
+	r     *registry
+	chain vaa.ChainID
+}
+
+func (c chainStats) SetNetworkStats(data *gossipv1.Heartbeat_Network) {
+	c.r.SetNetworkStats(c.chain, data)
+}
+
+// ChainStats returns the handle passed to the given chain's watcher to report its network status.
+func (r *registry) ChainStats(chain vaa.ChainID) common.NetworkStatsReporter {
+	return chainStats{r: r, chain: chain}
+}
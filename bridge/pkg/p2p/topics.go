@@ -0,0 +1,104 @@
+package p2p
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+)
+
+// Each message type is published on its own topic, such that high-volume message types do not compete with each other
+// for queue space and validation capacity. New message types should get a new topic.
+const (
+	TopicHeartbeat   = "heartbeat"
+	TopicObservation = "observation"
+
+	// TopicLegacyBroadcast is the single topic used for all message types before protocol version 1.
+	TopicLegacyBroadcast = "broadcast"
+)
+
+var (
+	p2pProtocolVersionsReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_p2p_protocol_versions_received_total",
+			Help: "Total number of p2p pubsub messages received, grouped by the sender's protocol version and compatibility",
+		}, []string{"version", "compatibility"})
+)
+
+func init() {
+	prometheus.MustRegister(p2pProtocolVersionsReceived)
+}
+
+// topicName returns the full pubsub topic name for the given network.
+func topicName(networkID string, topic string) string {
+	return fmt.Sprintf("%s/%s", networkID, topic)
+}
+
+// messageTopic returns the topic the given message is published on, or an empty string for unknown message types.
+func messageTopic(msg *gossipv1.GossipMessage) string {
+	switch msg.Message.(type) {
+	case *gossipv1.GossipMessage_SignedHeartbeat, *gossipv1.GossipMessage_Heartbeat:
+		return TopicHeartbeat
+	case *gossipv1.GossipMessage_SignedObservation:
+		return TopicObservation
+	default:
+		return ""
+	}
+}
+
+// protocolCompatibility classifies a peer's protocol version relative to ours.
+func protocolCompatibility(version uint32) string {
+	switch {
+	case version < common.ProtocolVersion:
+		return "older"
+	case version > common.ProtocolVersion:
+		return "newer"
+	default:
+		return "current"
+	}
+}
+
+// peerVersions tracks the last protocol version seen from each peer, such that version changes are logged once
+// instead of for every message.
+type peerVersions struct {
+	mu       sync.Mutex
+	versions map[peer.ID]uint32
+}
+
+func newPeerVersions() *peerVersions {
+	return &peerVersions{versions: map[peer.ID]uint32{}}
+}
+
+// observe records the protocol version of a message sent by the given peer and warns
+// if the peer's version differs from ours.
+func (v *peerVersions) observe(logger *zap.Logger, from peer.ID, version uint32) {
+	compat := protocolCompatibility(version)
+	p2pProtocolVersionsReceived.WithLabelValues(fmt.Sprint(version), compat).Inc()
+
+	v.mu.Lock()
+	last, seen := v.versions[from]
+	v.versions[from] = version
+	v.mu.Unlock()
+
+	if seen && last == version {
+		return
+	}
+
+	switch compat {
+	case "older":
+		logger.Info("peer is running an outdated protocol version",
+			zap.String("from", from.String()),
+			zap.Uint32("version", version),
+			zap.Uint32("our_version", common.ProtocolVersion))
+	case "newer":
+		logger.Warn("peer is running a newer protocol version - is our node outdated?",
+			zap.String("from", from.String()),
+			zap.Uint32("version", version),
+			zap.Uint32("our_version", common.ProtocolVersion))
+	}
+}
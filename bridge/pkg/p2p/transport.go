@@ -0,0 +1,150 @@
+package p2p
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	connmgr "github.com/libp2p/go-libp2p-connmgr"
+	noise "github.com/libp2p/go-libp2p-noise"
+	libp2pquic "github.com/libp2p/go-libp2p-quic-transport"
+	libp2ptls "github.com/libp2p/go-libp2p-tls"
+	tcp "github.com/libp2p/go-tcp-transport"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// Supported transports.
+const (
+	TransportQUIC = "quic"
+	TransportTCP  = "tcp"
+)
+
+// Supported security protocols for the TCP transport. QUIC always uses its built-in TLS 1.3 handshake.
+const (
+	SecurityTLS   = "tls"
+	SecurityNoise = "noise"
+)
+
+// ConnManagerConfig configures the connection manager, which trims connections down to LowWater
+// once HighWater is exceeded. Connections younger than GracePeriod are never trimmed.
+type ConnManagerConfig struct {
+	LowWater    int
+	HighWater   int
+	GracePeriod time.Duration
+}
+
+// DefaultConnManagerConfig is used when HostConfig.ConnManager is unset.
+var DefaultConnManagerConfig = ConnManagerConfig{
+	LowWater:    100,
+	HighWater:   400,
+	GracePeriod: time.Minute,
+}
+
+// IsValidTransport returns whether the given transport name is supported.
+func IsValidTransport(t string) bool {
+	return t == TransportQUIC || t == TransportTCP
+}
+
+// IsValidSecurity returns whether the given security protocol name is supported.
+func IsValidSecurity(s string) bool {
+	return s == SecurityTLS || s == SecurityNoise
+}
+
+// DefaultListenAddrs returns wildcard IPv4 and IPv6 listen addresses on the given port for each transport.
+func DefaultListenAddrs(transports []string, port uint) []string {
+	var addrs []string
+	for _, t := range transports {
+		switch t {
+		case TransportQUIC:
+			addrs = append(addrs,
+				fmt.Sprintf("/ip4/0.0.0.0/udp/%d/quic", port),
+				fmt.Sprintf("/ip6/::/udp/%d/quic", port))
+		case TransportTCP:
+			addrs = append(addrs,
+				fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", port),
+				fmt.Sprintf("/ip6/::/tcp/%d", port))
+		}
+	}
+	return addrs
+}
+
+// transportOptions returns the libp2p options for the host's transports, security protocols,
+// listen and announce addresses and connection manager.
+func transportOptions(cfg HostConfig) ([]libp2p.Option, error) {
+	transports := cfg.Transports
+	if len(transports) == 0 {
+		if cfg.PSK != nil {
+			transports = []string{TransportTCP}
+		} else {
+			transports = []string{TransportQUIC}
+		}
+	}
+
+	security := cfg.Security
+	if len(security) == 0 {
+		security = []string{SecurityTLS}
+	}
+
+	listenAddrs := cfg.ListenAddrs
+	if len(listenAddrs) == 0 {
+		listenAddrs = DefaultListenAddrs(transports, cfg.Port)
+	}
+
+	cm := cfg.ConnManager
+	if cm == (ConnManagerConfig{}) {
+		cm = DefaultConnManagerConfig
+	}
+
+	opts := []libp2p.Option{
+		libp2p.ListenAddrStrings(listenAddrs...),
+
+		// Let's prevent our peer from having too many
+		// connections by attaching a connection manager.
+		libp2p.ConnectionManager(connmgr.NewConnManager(cm.LowWater, cm.HighWater, cm.GracePeriod)),
+	}
+
+	for _, t := range transports {
+		switch t {
+		case TransportQUIC:
+			if cfg.PSK != nil {
+				// https://github.com/libp2p/go-libp2p-quic-transport/issues/59
+				return nil, fmt.Errorf("the QUIC transport does not support private networks")
+			}
+			opts = append(opts, libp2p.Transport(libp2pquic.NewTransport))
+		case TransportTCP:
+			opts = append(opts, libp2p.Transport(tcp.NewTCPTransport))
+		default:
+			return nil, fmt.Errorf("unsupported transport %q", t)
+		}
+	}
+
+	// Security protocols are negotiated in order of preference.
+	for _, s := range security {
+		switch s {
+		case SecurityTLS:
+			opts = append(opts, libp2p.Security(libp2ptls.ID, libp2ptls.New))
+		case SecurityNoise:
+			opts = append(opts, libp2p.Security(noise.ID, noise.New))
+		default:
+			return nil, fmt.Errorf("unsupported security protocol %q", s)
+		}
+	}
+
+	if len(cfg.AnnounceAddrs) > 0 {
+		announce := make([]multiaddr.Multiaddr, len(cfg.AnnounceAddrs))
+		for i, s := range cfg.AnnounceAddrs {
+			a, err := multiaddr.NewMultiaddr(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid announce address %q: %w", s, err)
+			}
+			announce[i] = a
+		}
+
+		// Advertise the announce addresses instead of our listen addresses, for nodes behind NAT or a load balancer.
+		opts = append(opts, libp2p.AddrsFactory(func([]multiaddr.Multiaddr) []multiaddr.Multiaddr {
+			return announce
+		}))
+	}
+
+	return opts, nil
+}
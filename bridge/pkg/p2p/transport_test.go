@@ -0,0 +1,75 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultListenAddrs(t *testing.T) {
+	assert.Equal(t, []string{
+		"/ip4/0.0.0.0/udp/8999/quic",
+		"/ip6/::/udp/8999/quic",
+		"/ip4/0.0.0.0/tcp/8999",
+		"/ip6/::/tcp/8999",
+	}, DefaultListenAddrs([]string{TransportQUIC, TransportTCP}, 8999))
+}
+
+func TestTransportOptionsInvalid(t *testing.T) {
+	_, err := transportOptions(HostConfig{Transports: []string{"udp"}})
+	assert.Error(t, err)
+
+	_, err = transportOptions(HostConfig{Security: []string{"plaintext"}})
+	assert.Error(t, err)
+
+	_, err = transportOptions(HostConfig{AnnounceAddrs: []string{"not a multiaddr"}})
+	assert.Error(t, err)
+
+	_, err = transportOptions(HostConfig{Transports: []string{TransportQUIC}, PSK: make([]byte, 32)})
+	assert.Error(t, err)
+}
+
+func newTestHost(t *testing.T, ctx context.Context, cfg HostConfig) host.Host {
+	priv, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	require.NoError(t, err)
+
+	cfg.NetworkID = "/wormhole/test"
+	h, err := NewHost(ctx, priv, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { h.Close() })
+	return h
+}
+
+func TestNewHostTCPNoise(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := HostConfig{
+		Transports:  []string{TransportTCP},
+		Security:    []string{SecurityNoise},
+		ListenAddrs: []string{"/ip4/127.0.0.1/tcp/0"},
+	}
+	h1 := newTestHost(t, ctx, cfg)
+	h2 := newTestHost(t, ctx, cfg)
+
+	require.NoError(t, h2.Connect(ctx, peer.AddrInfo{ID: h1.ID(), Addrs: h1.Addrs()}))
+	assert.Len(t, h1.Network().ConnsToPeer(h2.ID()), 1)
+}
+
+func TestNewHostAnnounceAddrs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := newTestHost(t, ctx, HostConfig{
+		Transports:    []string{TransportTCP},
+		ListenAddrs:   []string{"/ip4/127.0.0.1/tcp/0"},
+		AnnounceAddrs: []string{"/dns4/guardian.example.com/tcp/8999"},
+	})
+
+	assert.Equal(t, []string{"/dns4/guardian.example.com/tcp/8999"}, hostAddrs(h))
+}
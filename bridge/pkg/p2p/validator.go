@@ -0,0 +1,169 @@
+package p2p
+
+import (
+	"context"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+)
+
+// Gossip messages are validated before gossipsub propagates them, such that invalid observations are dropped by
+// the first honest node instead of flooding the whole mesh. Rejected messages count against the forwarding peer's
+// score, and peers with a low enough score are pruned from the mesh and eventually graylisted.
+//
+// The validator only performs stateless checks against the current guardian set. Full verification, including
+// signatures by previous guardian sets during a guardian set update, remains the processor's job.
+
+var (
+	p2pMessagesValidated = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_p2p_messages_validated_total",
+			Help: "Total number of pubsub messages validated, grouped by topic, result and reason",
+		}, []string{"topic", "result", "reason"})
+)
+
+func init() {
+	prometheus.MustRegister(p2pMessagesValidated)
+}
+
+// validateGossipMessage decides whether a gossip message received on the given topic should be delivered and
+// propagated. It returns the validation result along with a short reason for metrics.
+func validateGossipMessage(topic string, data []byte, gs *common.GuardianSet) (pubsub.ValidationResult, string) {
+	var msg gossipv1.GossipMessage
+	if err := proto.Unmarshal(data, &msg); err != nil {
+		return pubsub.ValidationReject, "invalid_message"
+	}
+
+	// Known message types must be sent on their dedicated topic. The legacy topic carries all message types.
+	if t := messageTopic(&msg); t != "" && topic != TopicLegacyBroadcast && t != topic {
+		return pubsub.ValidationReject, "wrong_topic"
+	}
+
+	switch m := msg.Message.(type) {
+	case *gossipv1.GossipMessage_SignedObservation:
+		return validateSignedObservation(m.SignedObservation, gs)
+	case *gossipv1.GossipMessage_SignedHeartbeat:
+		// Heartbeats failing signature verification are still delivered (flagged as unverified) during the
+		// signed heartbeat rollout, but must at least be decodable.
+		if _, err := decodeSignedHeartbeat(m.SignedHeartbeat); err != nil {
+			return pubsub.ValidationReject, "invalid_heartbeat"
+		}
+		return pubsub.ValidationAccept, "heartbeat"
+	case *gossipv1.GossipMessage_Heartbeat:
+		return pubsub.ValidationAccept, "heartbeat_legacy"
+	default:
+		// Could be a message type introduced by a newer release - do not penalize the sender.
+		return pubsub.ValidationAccept, "unknown"
+	}
+}
+
+// validateSignedObservation verifies an observation's signature and checks that it was signed by a member of the
+// given guardian set. Malformed or forged observations are rejected. Observations by guardians outside of our
+// guardian set are ignored without penalty, since it may be our guardian set that is outdated.
+func validateSignedObservation(o *gossipv1.SignedObservation, gs *common.GuardianSet) (pubsub.ValidationResult, string) {
+	if len(o.Hash) != 32 {
+		return pubsub.ValidationReject, "invalid_hash"
+	}
+
+	pk, err := ethcrypto.Ecrecover(o.Hash, o.Signature)
+	if err != nil {
+		return pubsub.ValidationReject, "invalid_signature"
+	}
+
+	signer := ethcommon.BytesToAddress(ethcrypto.Keccak256(pk[1:])[12:])
+	if signer != ethcommon.BytesToAddress(o.Addr) {
+		return pubsub.ValidationReject, "pubkey_mismatch"
+	}
+
+	if gs == nil {
+		return pubsub.ValidationIgnore, "uninitialized_guardian_set"
+	}
+
+	if _, ok := gs.KeyIndex(signer); !ok {
+		return pubsub.ValidationIgnore, "unknown_guardian"
+	}
+
+	return pubsub.ValidationAccept, "observation"
+}
+
+// newValidator returns a pubsub validator for the given topic. Messages published by ourselves are always accepted.
+// If gate is non-nil (private network mode), messages relayed by peers which have not been admitted are ignored.
+func newValidator(topic string, self peer.ID, gst *common.GuardianSetState, gate *Gate) pubsub.ValidatorEx {
+	return func(ctx context.Context, from peer.ID, m *pubsub.Message) pubsub.ValidationResult {
+		if from == self {
+			return pubsub.ValidationAccept
+		}
+
+		if gate != nil && !gate.Admitted(from) {
+			p2pMessagesValidated.WithLabelValues(topic, "ignore", "unauthenticated_peer").Inc()
+			return pubsub.ValidationIgnore
+		}
+
+		r, reason := validateGossipMessage(topic, m.Data, gst.Get())
+
+		switch r {
+		case pubsub.ValidationAccept:
+			p2pMessagesValidated.WithLabelValues(topic, "accept", reason).Inc()
+		case pubsub.ValidationIgnore:
+			p2pMessagesValidated.WithLabelValues(topic, "ignore", reason).Inc()
+		case pubsub.ValidationReject:
+			p2pMessagesValidated.WithLabelValues(topic, "reject", reason).Inc()
+		}
+
+		return r
+	}
+}
+
+// peerScoreParams returns the gossipsub peer scoring parameters for the given topics. Scoring is deliberately
+// conservative and only penalizes peers for invalid messages and protocol misbehaviour - guardian traffic
+// is too bursty to reward or penalize peers for message delivery rates.
+func peerScoreParams(topics []string) (*pubsub.PeerScoreParams, *pubsub.PeerScoreThresholds) {
+	topicParams := make(map[string]*pubsub.TopicScoreParams, len(topics))
+	for _, topic := range topics {
+		topicParams[topic] = &pubsub.TopicScoreParams{
+			TopicWeight:       1,
+			TimeInMeshQuantum: time.Second,
+
+			// P4: each rejected message adds to a counter whose square is weighted, so a handful
+			// of invalid messages gets a peer graylisted. The penalty decays within an hour.
+			InvalidMessageDeliveriesWeight: -10,
+			InvalidMessageDeliveriesDecay:  pubsub.ScoreParameterDecay(time.Hour),
+		}
+	}
+
+	params := &pubsub.PeerScoreParams{
+		Topics: topicParams,
+
+		AppSpecificScore:  func(p peer.ID) float64 { return 0 },
+		AppSpecificWeight: 1,
+
+		// P7: gossipsub protocol misbehaviour (like re-grafting during backoff).
+		BehaviourPenaltyWeight:    -10,
+		BehaviourPenaltyThreshold: 6,
+		BehaviourPenaltyDecay:     pubsub.ScoreParameterDecay(10 * time.Minute),
+
+		DecayInterval: pubsub.DefaultDecayInterval,
+		DecayToZero:   pubsub.DefaultDecayToZero,
+
+		// Remember scores of disconnected peers, such that a peer cannot reset its score by reconnecting.
+		RetainScore: time.Hour,
+	}
+
+	thresholds := &pubsub.PeerScoreThresholds{
+		GossipThreshold:             -100,
+		PublishThreshold:            -500,
+		GraylistThreshold:           -1000,
+		AcceptPXThreshold:           100,
+		OpportunisticGraftThreshold: 5,
+	}
+
+	return params, thresholds
+}
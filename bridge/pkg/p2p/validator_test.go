@@ -0,0 +1,64 @@
+package p2p
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+)
+
+func TestValidateGossipMessage(t *testing.T) {
+	gk, addr := newTestGuardian(t)
+	otherGk, otherAddr := newTestGuardian(t)
+	gs := &common.GuardianSet{Keys: []ethcommon.Address{addr}, Index: 1}
+
+	hash := ethcrypto.Keccak256([]byte("lockup"))
+
+	observation := func(gk *ecdsa.PrivateKey, addr ethcommon.Address) []byte {
+		sig, err := ethcrypto.Sign(hash, gk)
+		require.NoError(t, err)
+
+		b, err := proto.Marshal(&gossipv1.GossipMessage{Message: &gossipv1.GossipMessage_SignedObservation{
+			SignedObservation: &gossipv1.SignedObservation{Addr: addr.Bytes(), Hash: hash, Signature: sig},
+		}})
+		require.NoError(t, err)
+		return b
+	}
+
+	tests := []struct {
+		name   string
+		data   []byte
+		gs     *common.GuardianSet
+		result pubsub.ValidationResult
+	}{
+		{"valid observation", observation(gk, addr), gs, pubsub.ValidationAccept},
+		{"address mismatch", observation(gk, otherAddr), gs, pubsub.ValidationReject},
+		{"unknown guardian", observation(otherGk, otherAddr), gs, pubsub.ValidationIgnore},
+		{"uninitialized guardian set", observation(gk, addr), nil, pubsub.ValidationIgnore},
+		{"garbage", []byte{0xff, 0xff, 0xff}, gs, pubsub.ValidationReject},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _ := validateGossipMessage(TopicObservation, tc.data, tc.gs)
+			assert.Equal(t, tc.result, r)
+
+			// The legacy topic carries all message types.
+			r, _ = validateGossipMessage(TopicLegacyBroadcast, tc.data, tc.gs)
+			assert.Equal(t, tc.result, r)
+		})
+	}
+
+	// Observations must not be sent on the heartbeat topic.
+	r, reason := validateGossipMessage(TopicHeartbeat, observation(gk, addr), gs)
+	assert.Equal(t, pubsub.ValidationReject, r)
+	assert.Equal(t, "wrong_topic", reason)
+}
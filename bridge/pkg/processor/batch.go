@@ -0,0 +1,124 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+var (
+	lockupBatchesSignedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_lockup_batches_signed_total",
+			Help: "Total number of lockup batches that were successfully signed",
+		},
+		[]string{"source_chain"})
+	lockupBatchSize = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "wormhole_lockup_batch_size",
+			Help:    "Number of lockups in signed lockup batches",
+			Buckets: prometheus.ExponentialBuckets(2, 2, 8),
+		})
+)
+
+func init() {
+	prometheus.MustRegister(lockupBatchesSignedTotal)
+	prometheus.MustRegister(lockupBatchSize)
+}
+
+// handleLockBatch processes the lockups confirmed in a single source chain block and signs a batch VAA
+// committing to all of them. Blocks with a single lockup are signed as a regular transfer VAA only.
+//
+// Every lockup in the batch is signed as a regular transfer VAA as well. No target chain contract verifies
+// inclusion proofs yet, so the batch is an additional commitment rather than a replacement.
+func (p *Processor) handleLockBatch(ctx context.Context, b *common.ChainLockBatch) {
+	if len(b.Locks) == 1 {
+		p.handleLockup(ctx, b.Locks[0])
+		return
+	}
+
+	// Every guardian needs to commit to the same order, regardless of the order the watcher found them in.
+	locks := make([]*common.ChainLock, len(b.Locks))
+	copy(locks, b.Locks)
+	sort.Slice(locks, func(i, j int) bool {
+		if c := bytes.Compare(locks[i].TxHash[:], locks[j].TxHash[:]); c != 0 {
+			return c < 0
+		}
+		return locks[i].Nonce < locks[j].Nonce
+	})
+
+	var transfers []vaa.Payload
+	for _, k := range locks {
+		if k.SourceChain != b.SourceChain {
+			p.logger.Error("ignoring lockup from another chain in batch",
+				zap.Stringer("batch_source_chain", b.SourceChain),
+				zap.Stringer("source_chain", k.SourceChain),
+				zap.Stringer("txhash", k.TxHash))
+			continue
+		}
+		if t, ok := p.observeLockup(ctx, k); ok {
+			p.signLockup(k, t)
+			transfers = append(transfers, t)
+		}
+	}
+	if len(transfers) == 0 {
+		return
+	}
+
+	// Batches larger than the limit are split into consecutive chunks, each signed as its own batch.
+	for len(transfers) > 0 {
+		n := len(transfers)
+		if n > vaa.MaxBatchSize {
+			n = vaa.MaxBatchSize
+		}
+		p.signBatch(b, transfers[:n])
+		transfers = transfers[n:]
+	}
+}
+
+func (p *Processor) signBatch(b *common.ChainLockBatch, transfers []vaa.Payload) {
+	body, err := vaa.NewBodyBatch(b.SourceChain, b.BlockHash, transfers)
+	if err != nil {
+		panic(err)
+	}
+
+	v := &vaa.VAA{
+		Version:          vaa.SupportedVAAVersion,
+		GuardianSetIndex: p.gs.Index,
+		Signatures:       nil,
+		Timestamp:        b.Timestamp,
+		Payload:          body,
+	}
+
+	digest, err := v.SigningMsg()
+	if err != nil {
+		panic(err)
+	}
+
+	s, err := crypto.Sign(digest.Bytes(), p.gk)
+	if err != nil {
+		panic(err)
+	}
+
+	p.logger.Info("observed and signed confirmed lockup batch",
+		zap.Stringer("source_chain", b.SourceChain),
+		zap.Stringer("block_hash", b.BlockHash),
+		zap.Int("lockups", len(transfers)),
+		zap.Stringer("root", body.Root),
+		zap.String("digest", hex.EncodeToString(digest.Bytes())),
+		zap.String("signature", hex.EncodeToString(s)))
+
+	lockupBatchesSignedTotal.WithLabelValues(b.SourceChain.String()).Inc()
+	lockupBatchSize.Observe(float64(len(transfers)))
+
+	// Batches are not tracked for divergence, since their identity is the block rather than a single event.
+	p.broadcastSignature(v, s, nil)
+}
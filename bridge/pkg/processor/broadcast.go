@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/certusone/wormhole/bridge/pkg/common"
 	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
 	"github.com/certusone/wormhole/bridge/pkg/vaa"
 )
@@ -25,7 +26,8 @@ func init() {
 	prometheus.MustRegister(observationsBroadcastTotal)
 }
 
-func (p *Processor) broadcastSignature(v *vaa.VAA, signature []byte) {
+// broadcastSignature broadcasts our signature of v. If the VAA results from an on-chain event, e is its identity.
+func (p *Processor) broadcastSignature(v *vaa.VAA, signature []byte, e *eventID) {
 	digest, err := v.SigningMsg()
 	if err != nil {
 		panic(err)
@@ -37,7 +39,25 @@ func (p *Processor) broadcastSignature(v *vaa.VAA, signature []byte) {
 		Signature: signature,
 	}
 
-	w := gossipv1.GossipMessage{Message: &gossipv1.GossipMessage_SignedObservation{SignedObservation: &obsv}}
+	if e != nil {
+		obsv.SourceChain = uint32(e.SourceChain)
+		if e.Message {
+			obsv.EmitterAddress = e.EmitterAddress[:]
+			obsv.Sequence = e.Sequence
+		} else {
+			obsv.TxHash = e.TxHash.Bytes()
+			obsv.Nonce = e.Nonce
+		}
+		obsv.EventSignature, err = crypto.Sign(eventSigningMsg(digest.Bytes(), *e).Bytes(), p.gk)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	w := gossipv1.GossipMessage{
+		ProtocolVersion: common.ProtocolVersion,
+		Message:         &gossipv1.GossipMessage_SignedObservation{SignedObservation: &obsv},
+	}
 
 	msg, err := proto.Marshal(&w)
 	if err != nil {
@@ -51,13 +71,17 @@ func (p *Processor) broadcastSignature(v *vaa.VAA, signature []byte) {
 
 	if p.state.vaaSignatures[hash] == nil {
 		p.state.vaaSignatures[hash] = &vaaState{
-			firstObserved: time.Now(),
-			signatures:    map[ethcommon.Address][]byte{},
-			source:        "loopback",
+			firstObserved:  time.Now(),
+			signatures:     map[ethcommon.Address][]byte{},
+			signatureTimes: map[ethcommon.Address]time.Time{},
+			source:         "loopback",
 		}
 	}
 
 	p.state.vaaSignatures[hash].ourVAA = v
+	if p.state.vaaSignatures[hash].ourObservation.IsZero() {
+		p.state.vaaSignatures[hash].ourObservation = time.Now()
+	}
 	p.state.vaaSignatures[hash].ourMsg = msg
 	p.state.vaaSignatures[hash].gs = p.gs // guaranteed to match ourVAA - there's no concurrent access to p.gs
 
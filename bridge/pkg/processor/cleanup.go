@@ -82,6 +82,7 @@ func (p *Processor) handleCleanup(ctx context.Context) {
 					aggregationStateFulfillment.WithLabelValues(k.Hex(), s.source, "missing").Inc()
 				}
 			}
+			observeSignatureLag(s)
 		case s.submitted && delta.Hours() >= 1:
 			// We could delete submitted VAAs right away, but then we'd lose context about additional (late)
 			// observation that come in. Therefore, keep it for a reasonable amount of time.
@@ -116,4 +117,11 @@ func (p *Processor) handleCleanup(ctx context.Context) {
 			}
 		}
 	}
+
+	// Events are only needed while their observations are still arriving.
+	for e, s := range p.state.events {
+		if time.Since(s.firstObserved) >= time.Hour {
+			delete(p.state.events, e)
+		}
+	}
 }
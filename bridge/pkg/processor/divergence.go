@@ -0,0 +1,218 @@
+package processor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+var (
+	eventDivergencesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_event_divergences_total",
+			Help: "Total number of conflicting hashes signed by guardians for an already observed event, grouped by source chain",
+		}, []string{"source_chain"})
+	eventDivergentSignaturesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_event_divergent_signatures_by_guardian_total",
+			Help: "Total number of signatures for events with conflicting hashes, grouped by guardian address",
+		}, []string{"addr"})
+	eventEquivocationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_event_equivocations_by_guardian_total",
+			Help: "Total number of times a guardian signed more than one hash for the same event, grouped by guardian address",
+		}, []string{"addr"})
+)
+
+func init() {
+	prometheus.MustRegister(eventDivergencesTotal)
+	prometheus.MustRegister(eventDivergentSignaturesTotal)
+	prometheus.MustRegister(eventEquivocationsTotal)
+}
+
+type (
+	// eventID identifies an observed event independently of the hash the guardians signed for it.
+	eventID struct {
+		SourceChain vaa.ChainID
+
+		// Lockups are identified by the hash of their transaction and their nonce.
+		TxHash ethcommon.Hash
+		Nonce  uint32
+
+		// Message publications are identified by their emitter and sequence instead. A transaction may publish
+		// several messages, but the sequence is unique per emitter.
+		Message        bool
+		EmitterAddress vaa.Address
+		Sequence       uint64
+	}
+
+	// eventState is the local view of the hashes signed for a given event.
+	eventState struct {
+		// First time the event was seen.
+		firstObserved time.Time
+		// Guardians which signed each (hex-encoded) hash for the event. More than one hash means that the
+		// guardians disagree on what they observed.
+		signers map[string]map[ethcommon.Address]bool
+	}
+
+	eventMap map[eventID]*eventState
+)
+
+func (e eventID) String() string {
+	if e.Message {
+		return fmt.Sprintf("%s/%s/%d", e.SourceChain, e.EmitterAddress, e.Sequence)
+	}
+	return fmt.Sprintf("%s/%s/%d", e.SourceChain, e.TxHash.Hex(), e.Nonce)
+}
+
+// eventSigningMsg returns the digest signed by an observation's event signature, binding the event to the
+// observation's hash.
+func eventSigningMsg(hash []byte, e eventID) ethcommon.Hash {
+	if e.Message {
+		var sequence [8]byte
+		binary.BigEndian.PutUint64(sequence[:], e.Sequence)
+		return crypto.Keccak256Hash([]byte("wormhole-message-event"), hash, []byte{uint8(e.SourceChain)},
+			e.EmitterAddress[:], sequence[:])
+	}
+
+	var nonce [4]byte
+	binary.BigEndian.PutUint32(nonce[:], e.Nonce)
+	return crypto.Keccak256Hash([]byte("wormhole-event"), hash, []byte{uint8(e.SourceChain)}, e.TxHash[:], nonce[:])
+}
+
+// observationEvent returns the event identity of an observation signed by addr. Returns false if the observation
+// has no event identity, and an error if it's malformed or not signed by addr.
+func observationEvent(m *gossipv1.SignedObservation, addr ethcommon.Address) (eventID, bool, error) {
+	if len(m.TxHash) == 0 && len(m.EmitterAddress) == 0 {
+		return eventID{}, false, nil
+	}
+
+	if m.SourceChain == 0 || m.SourceChain > 255 {
+		return eventID{}, false, fmt.Errorf("invalid source chain: %d", m.SourceChain)
+	}
+
+	e := eventID{SourceChain: vaa.ChainID(m.SourceChain)}
+	switch {
+	case len(m.TxHash) != 0 && len(m.EmitterAddress) != 0:
+		return eventID{}, false, fmt.Errorf("both tx hash and emitter address are set")
+	case len(m.TxHash) != 0:
+		if len(m.TxHash) != 32 {
+			return eventID{}, false, fmt.Errorf("invalid tx hash length: %d", len(m.TxHash))
+		}
+		e.TxHash = ethcommon.BytesToHash(m.TxHash)
+		e.Nonce = m.Nonce
+	default:
+		if len(m.EmitterAddress) != 32 {
+			return eventID{}, false, fmt.Errorf("invalid emitter address length: %d", len(m.EmitterAddress))
+		}
+		e.Message = true
+		copy(e.EmitterAddress[:], m.EmitterAddress)
+		e.Sequence = m.Sequence
+	}
+
+	pk, err := crypto.Ecrecover(eventSigningMsg(m.Hash, e).Bytes(), m.EventSignature)
+	if err != nil {
+		return eventID{}, false, fmt.Errorf("invalid event signature: %w", err)
+	}
+	if ethcommon.BytesToAddress(crypto.Keccak256(pk[1:])[12:]) != addr {
+		return eventID{}, false, fmt.Errorf("event signature does not match observation address")
+	}
+
+	return e, true, nil
+}
+
+// trackEvent records the hash a guardian signed for the observation's event, if it has one, and alerts if
+// guardians signed conflicting hashes for the same event. The observation must be verified to be signed by addr.
+func (p *Processor) trackEvent(m *gossipv1.SignedObservation, hash string, addr ethcommon.Address) {
+	e, ok, err := observationEvent(m, addr)
+	if err != nil {
+		// Honest guardians always send a valid event identity, if any. Still accept the observation itself,
+		// since its signature is valid.
+		p.logger.Warn("ignoring invalid event identity on observation",
+			zap.String("digest", hash),
+			zap.String("addr", addr.Hex()),
+			zap.Error(err))
+		observationsFailedTotal.WithLabelValues("invalid_event").Inc()
+		return
+	}
+	if !ok {
+		return
+	}
+
+	s := p.state.events[e]
+	if s == nil {
+		s = &eventState{
+			firstObserved: time.Now(),
+			signers:       map[string]map[ethcommon.Address]bool{},
+		}
+		p.state.events[e] = s
+	}
+
+	if s.signers[hash][addr] {
+		// Retransmission.
+		return
+	}
+
+	newHash := s.signers[hash] == nil
+	if newHash {
+		s.signers[hash] = map[ethcommon.Address]bool{}
+	}
+	s.signers[hash][addr] = true
+
+	if len(s.signers) < 2 {
+		return
+	}
+
+	equivocation := false
+	for h, signers := range s.signers {
+		if h != hash && signers[addr] {
+			equivocation = true
+		}
+	}
+
+	if newHash {
+		eventDivergencesTotal.WithLabelValues(e.SourceChain.String()).Inc()
+	}
+	if newHash && len(s.signers) == 2 {
+		// The event just diverged - count everyone who signed it so far.
+		for _, signers := range s.signers {
+			for a := range signers {
+				eventDivergentSignaturesTotal.WithLabelValues(a.Hex()).Inc()
+			}
+		}
+	} else {
+		eventDivergentSignaturesTotal.WithLabelValues(addr.Hex()).Inc()
+	}
+	if equivocation {
+		eventEquivocationsTotal.WithLabelValues(addr.Hex()).Inc()
+	}
+
+	p.logger.Error("guardians signed conflicting hashes for the same event",
+		zap.Stringer("source_chain", e.SourceChain),
+		zap.Stringer("event", e),
+		zap.String("guardian", addr.Hex()),
+		zap.String("digest", hash),
+		zap.Bool("equivocation", equivocation),
+		zap.Any("digests", s.signersByHash()))
+}
+
+// signersByHash returns the sorted addresses of the guardians which signed each hash of the event.
+func (s *eventState) signersByHash() map[string][]string {
+	r := make(map[string][]string, len(s.signers))
+	for h, signers := range s.signers {
+		for a := range signers {
+			r[h] = append(r[h], a.Hex())
+		}
+		sort.Strings(r[h])
+	}
+	return r
+}
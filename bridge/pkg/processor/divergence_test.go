@@ -0,0 +1,139 @@
+package processor
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+func signedEventObservation(t *testing.T, gk *ecdsa.PrivateKey, hash ethcommon.Hash, e eventID) *gossipv1.SignedObservation {
+	sig, err := crypto.Sign(hash.Bytes(), gk)
+	require.NoError(t, err)
+	esig, err := crypto.Sign(eventSigningMsg(hash.Bytes(), e).Bytes(), gk)
+	require.NoError(t, err)
+
+	m := &gossipv1.SignedObservation{
+		Addr:           crypto.PubkeyToAddress(gk.PublicKey).Bytes(),
+		Hash:           hash.Bytes(),
+		Signature:      sig,
+		SourceChain:    uint32(e.SourceChain),
+		EventSignature: esig,
+	}
+	if e.Message {
+		m.EmitterAddress = e.EmitterAddress[:]
+		m.Sequence = e.Sequence
+	} else {
+		m.TxHash = e.TxHash.Bytes()
+		m.Nonce = e.Nonce
+	}
+	return m
+}
+
+func TestTrackEvent(t *testing.T) {
+	p := &Processor{logger: zap.NewNop(), state: &aggregationState{vaaMap{}, eventMap{}}}
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		var err error
+		keys[i], err = crypto.GenerateKey()
+		require.NoError(t, err)
+	}
+	addr := func(i int) ethcommon.Address { return crypto.PubkeyToAddress(keys[i].PublicKey) }
+
+	e := eventID{SourceChain: vaa.ChainIDEthereum, TxHash: ethcommon.Hash{1}, Nonce: 7}
+	h1, h2 := ethcommon.Hash{0xaa}, ethcommon.Hash{0xbb}
+	divergences := eventDivergencesTotal.WithLabelValues(e.SourceChain.String())
+	before := testutil.ToFloat64(divergences)
+
+	track := func(i int, h ethcommon.Hash) {
+		p.trackEvent(signedEventObservation(t, keys[i], h, e), hex.EncodeToString(h.Bytes()), addr(i))
+	}
+
+	// Agreement.
+	track(0, h1)
+	track(1, h1)
+	track(1, h1)
+	require.Len(t, p.state.events[e].signers, 1)
+	assert.Equal(t, before, testutil.ToFloat64(divergences))
+
+	// Guardian 2 signs a different hash.
+	track(2, h2)
+	require.Len(t, p.state.events[e].signers, 2)
+	assert.Equal(t, before+1, testutil.ToFloat64(divergences))
+	assert.Equal(t, []string{addr(2).Hex()}, p.state.events[e].signersByHash()[hex.EncodeToString(h2.Bytes())])
+
+	// Guardian 0 equivocates.
+	equivocations := eventEquivocationsTotal.WithLabelValues(addr(0).Hex())
+	track(0, h2)
+	assert.Equal(t, float64(1), testutil.ToFloat64(equivocations))
+	assert.Equal(t, before+1, testutil.ToFloat64(divergences))
+}
+
+func TestTrackEventIgnoresInvalidIdentity(t *testing.T) {
+	p := &Processor{logger: zap.NewNop(), state: &aggregationState{vaaMap{}, eventMap{}}}
+
+	gk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(gk.PublicKey)
+	e := eventID{SourceChain: vaa.ChainIDSolana, TxHash: ethcommon.Hash{2}, Nonce: 1}
+	h := ethcommon.Hash{0xcc}
+
+	// No event identity.
+	m := signedEventObservation(t, gk, h, e)
+	m.TxHash, m.SourceChain, m.Nonce, m.EventSignature = nil, 0, 0, nil
+	p.trackEvent(m, hex.EncodeToString(h.Bytes()), addr)
+	assert.Empty(t, p.state.events)
+
+	// Event identity modified by a relaying node.
+	m = signedEventObservation(t, gk, h, e)
+	m.Nonce = 2
+	p.trackEvent(m, hex.EncodeToString(h.Bytes()), addr)
+	assert.Empty(t, p.state.events)
+
+	// Event signed by someone else.
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	m = signedEventObservation(t, other, h, e)
+	p.trackEvent(m, hex.EncodeToString(h.Bytes()), addr)
+	assert.Empty(t, p.state.events)
+}
+
+func TestTrackMessageEvent(t *testing.T) {
+	p := &Processor{logger: zap.NewNop(), state: &aggregationState{vaaMap{}, eventMap{}}}
+
+	gk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(gk.PublicKey)
+	h := ethcommon.Hash{0xdd}
+
+	// Two messages published by the same transaction are distinct events.
+	e1 := eventID{SourceChain: vaa.ChainIDEthereum, Message: true, EmitterAddress: vaa.Address{1}, Sequence: 1}
+	e2 := eventID{SourceChain: vaa.ChainIDEthereum, Message: true, EmitterAddress: vaa.Address{1}, Sequence: 2}
+	p.trackEvent(signedEventObservation(t, gk, h, e1), hex.EncodeToString(h.Bytes()), addr)
+	p.trackEvent(signedEventObservation(t, gk, h, e2), hex.EncodeToString(h.Bytes()), addr)
+	assert.Len(t, p.state.events, 2)
+	assert.Contains(t, p.state.events, e1)
+	assert.Contains(t, p.state.events, e2)
+
+	// Sequence modified by a relaying node.
+	m := signedEventObservation(t, gk, h, e1)
+	m.Sequence = 3
+	p.trackEvent(m, hex.EncodeToString(h.Bytes()), addr)
+	assert.Len(t, p.state.events, 2)
+
+	// An observation can't identify both a lockup and a message.
+	m = signedEventObservation(t, gk, h, e1)
+	m.TxHash = ethcommon.Hash{4}.Bytes()
+	p.trackEvent(m, hex.EncodeToString(h.Bytes()), addr)
+	assert.Len(t, p.state.events, 2)
+}
@@ -40,22 +40,15 @@ func init() {
 // handleLockup processes a lockup received from a chain and instantiates our deterministic copy of the VAA. A lockup
 // event may be received multiple times until it has been successfully completed.
 func (p *Processor) handleLockup(ctx context.Context, k *common.ChainLock) {
-	supervisor.Logger(ctx).Info("lockup confirmed",
-		zap.Stringer("source_chain", k.SourceChain),
-		zap.Stringer("target_chain", k.TargetChain),
-		zap.Stringer("source_addr", k.SourceAddress),
-		zap.Stringer("target_addr", k.TargetAddress),
-		zap.Stringer("token_chain", k.TokenChain),
-		zap.Stringer("token_addr", k.TokenAddress),
-		zap.Stringer("amount", k.Amount),
-		zap.Stringer("txhash", k.TxHash),
-		zap.Time("timestamp", k.Timestamp),
-	)
-
-	lockupsObservedTotal.With(prometheus.Labels{
-		"source_chain": k.SourceChain.String(),
-		"target_chain": k.TargetChain.String()}).Add(1)
+	payload, ok := p.observeLockup(ctx, k)
+	if !ok {
+		return
+	}
+	p.signLockup(k, payload)
+}
 
+// signLockup signs and broadcasts the transfer VAA of an observed lockup.
+func (p *Processor) signLockup(k *common.ChainLock, payload vaa.Payload) {
 	// All nodes will create the exact same VAA and sign its digest.
 	// Consensus is established on this digest.
 
@@ -64,19 +57,7 @@ func (p *Processor) handleLockup(ctx context.Context, k *common.ChainLock) {
 		GuardianSetIndex: p.gs.Index,
 		Signatures:       nil,
 		Timestamp:        k.Timestamp,
-		Payload: &vaa.BodyTransfer{
-			Nonce:         k.Nonce,
-			SourceChain:   k.SourceChain,
-			TargetChain:   k.TargetChain,
-			SourceAddress: k.SourceAddress,
-			TargetAddress: k.TargetAddress,
-			Asset: &vaa.AssetMeta{
-				Chain:    k.TokenChain,
-				Address:  k.TokenAddress,
-				Decimals: k.TokenDecimals,
-			},
-			Amount: k.Amount,
-		},
+		Payload:          payload,
 	}
 
 	// Generate digest of the unsigned VAA.
@@ -102,5 +83,77 @@ func (p *Processor) handleLockup(ctx context.Context, k *common.ChainLock) {
 		"source_chain": k.SourceChain.String(),
 		"target_chain": k.TargetChain.String()}).Add(1)
 
-	p.broadcastSignature(v, s)
+	e := lockupEventID(k)
+	p.broadcastSignature(v, s, &e)
+}
+
+// lockupEventID returns the ID of the event the lockup was observed in.
+func lockupEventID(k *common.ChainLock) eventID {
+	return eventID{SourceChain: k.SourceChain, TxHash: k.TxHash, Nonce: k.Nonce}
+}
+
+// observeLockup logs and counts a lockup and returns its transfer payload, or false if it is invalid or involves
+// a paused chain. Lockups involving a paused chain are deferred until the chain is unpaused.
+func (p *Processor) observeLockup(ctx context.Context, k *common.ChainLock) (vaa.Payload, bool) {
+	supervisor.Logger(ctx).Info("lockup confirmed",
+		zap.Stringer("source_chain", k.SourceChain),
+		zap.Stringer("target_chain", k.TargetChain),
+		zap.String("source_addr", k.SourceAddress.NativeString(k.SourceChain)),
+		zap.String("target_addr", k.TargetAddress.NativeString(k.TargetChain)),
+		zap.Stringer("token_chain", k.TokenChain),
+		zap.String("token_addr", k.TokenAddress.NativeString(k.TokenChain)),
+		zap.Stringer("amount", k.Amount),
+		zap.Stringer("fee", k.Fee),
+		zap.Int("payload_len", len(k.Payload)),
+		zap.Stringer("txhash", k.TxHash),
+		zap.Time("timestamp", k.Timestamp),
+	)
+
+	lockupsObservedTotal.With(prometheus.Labels{
+		"source_chain": k.SourceChain.String(),
+		"target_chain": k.TargetChain.String()}).Add(1)
+
+	// Chains are expected to reject these, but a VAA with an invalid payload would never be redeemable.
+	if len(k.Payload) > vaa.MaxTransferPayloadLength || (k.Fee != nil && k.Fee.Cmp(k.Amount) > 0) {
+		supervisor.Logger(ctx).Error("ignoring lockup with invalid payload or fee",
+			zap.Stringer("source_chain", k.SourceChain),
+			zap.Stringer("txhash", k.TxHash),
+			zap.Stringer("fee", k.Fee),
+			zap.Int("payload_len", len(k.Payload)))
+		return nil, false
+	}
+
+	if p.lockupPaused(k) {
+		return nil, false
+	}
+
+	return transferPayload(k), true
+}
+
+// transferPayload returns the payload of a lockup's transfer VAA.
+func transferPayload(k *common.ChainLock) vaa.Payload {
+	t := vaa.BodyTransfer{
+		Nonce:         k.Nonce,
+		SourceChain:   k.SourceChain,
+		TargetChain:   k.TargetChain,
+		SourceAddress: k.SourceAddress,
+		TargetAddress: k.TargetAddress,
+		Asset: &vaa.AssetMeta{
+			Chain:    k.TokenChain,
+			Address:  k.TokenAddress,
+			Decimals: k.TokenDecimals,
+		},
+		Amount: k.Amount,
+	}
+
+	var payload vaa.Payload = &t
+	if k.Payload != nil || k.Fee != nil {
+		payload = &vaa.BodyTransferWithPayload{
+			BodyTransfer: t,
+			Fee:          k.Fee,
+			Payload:      k.Payload,
+		}
+	}
+
+	return payload
 }
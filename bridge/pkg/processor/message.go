@@ -0,0 +1,102 @@
+package processor
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+var (
+	// SECURITY: emitter_chain is an untrusted uint8 value, resulting in at most 255 label values.
+
+	messagesObservedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_messages_observed_total",
+			Help: "Total number of message publications received on-chain",
+		},
+		[]string{"emitter_chain"})
+
+	messagesSignedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_messages_signed_total",
+			Help: "Total number of message publications that were successfully signed",
+		},
+		[]string{"emitter_chain"})
+)
+
+func init() {
+	prometheus.MustRegister(messagesObservedTotal)
+	prometheus.MustRegister(messagesSignedTotal)
+}
+
+// handleMessage processes a message publication received from a chain and instantiates our deterministic copy of
+// the VAA. Like lockups, a message may be received multiple times until it has been successfully completed.
+func (p *Processor) handleMessage(ctx context.Context, m *common.MessagePublication) {
+	p.logger.Info("message publication confirmed",
+		zap.Stringer("emitter_chain", m.EmitterChain),
+		zap.String("emitter_addr", m.EmitterAddress.NativeString(m.EmitterChain)),
+		zap.Uint64("sequence", m.Sequence),
+		zap.Uint8("consistency_level", m.ConsistencyLevel),
+		zap.Int("payload_len", len(m.Payload)),
+		zap.Stringer("txhash", m.TxHash),
+		zap.Time("timestamp", m.Timestamp),
+	)
+
+	messagesObservedTotal.WithLabelValues(m.EmitterChain.String()).Inc()
+
+	if len(m.Payload) > vaa.MaxMessagePayloadLength {
+		p.logger.Error("dropping message publication with oversized payload",
+			zap.Stringer("emitter_chain", m.EmitterChain),
+			zap.Stringer("txhash", m.TxHash),
+			zap.Int("payload_len", len(m.Payload)))
+		return
+	}
+
+	v := &vaa.VAA{
+		Version:          vaa.SupportedVAAVersion,
+		GuardianSetIndex: p.gs.Index,
+		Signatures:       nil,
+		Timestamp:        m.Timestamp,
+		Payload: &vaa.BodyMessage{
+			EmitterChain:     m.EmitterChain,
+			EmitterAddress:   m.EmitterAddress,
+			Sequence:         m.Sequence,
+			ConsistencyLevel: m.ConsistencyLevel,
+			Payload:          m.Payload,
+		},
+	}
+
+	// Generate digest of the unsigned VAA.
+	digest, err := v.SigningMsg()
+	if err != nil {
+		panic(err)
+	}
+
+	// Sign the digest using our node's guardian key.
+	s, err := crypto.Sign(digest.Bytes(), p.gk)
+	if err != nil {
+		panic(err)
+	}
+
+	p.logger.Info("observed and signed confirmed message publication",
+		zap.Stringer("emitter_chain", m.EmitterChain),
+		zap.Uint64("sequence", m.Sequence),
+		zap.Stringer("txhash", m.TxHash),
+		zap.String("digest", hex.EncodeToString(digest.Bytes())),
+		zap.String("signature", hex.EncodeToString(s)))
+
+	messagesSignedTotal.WithLabelValues(m.EmitterChain.String()).Inc()
+
+	p.broadcastSignature(v, s, &eventID{
+		SourceChain:    m.EmitterChain,
+		Message:        true,
+		EmitterAddress: m.EmitterAddress,
+		Sequence:       m.Sequence,
+	})
+}
@@ -3,21 +3,17 @@ package processor
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	bridge_common "github.com/certusone/wormhole/bridge/pkg/common"
-	"github.com/certusone/wormhole/bridge/pkg/qtum"
 	"github.com/prometheus/client_golang/prometheus"
 
-	"github.com/certusone/wormhole/bridge/pkg/terra"
-
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"go.uber.org/zap"
 
-	"github.com/certusone/wormhole/bridge/pkg/devnet"
 	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
 	"github.com/certusone/wormhole/bridge/pkg/vaa"
 )
@@ -163,6 +159,8 @@ func (p *Processor) handleObservation(ctx context.Context, m *gossipv1.SignedObs
 	// We can now count events by guardian without worry about cardinality explosions:
 	observationsReceivedByGuardianAddressTotal.WithLabelValues(their_addr.Hex()).Inc()
 
+	p.trackEvent(m, hash, their_addr)
+
 	// []byte isn't hashable in a map. Paying a small extra cost for encoding for easier debugging.
 	if p.state.vaaSignatures[hash] == nil {
 		// We haven't yet seen this event ourselves, and therefore do not know what the VAA looks like.
@@ -176,13 +174,17 @@ func (p *Processor) handleObservation(ctx context.Context, m *gossipv1.SignedObs
 		observationsUnknownLockupTotal.Inc()
 
 		p.state.vaaSignatures[hash] = &vaaState{
-			firstObserved: time.Now(),
-			signatures:    map[common.Address][]byte{},
-			source:        "unknown",
+			firstObserved:  time.Now(),
+			signatures:     map[common.Address][]byte{},
+			signatureTimes: map[common.Address]time.Time{},
+			source:         "unknown",
 		}
 	}
 
 	p.state.vaaSignatures[hash].signatures[their_addr] = m.Signature
+	if _, ok := p.state.vaaSignatures[hash].signatureTimes[their_addr]; !ok {
+		p.state.vaaSignatures[hash].signatureTimes[their_addr] = time.Now()
+	}
 
 	// Aggregate all valid signatures into a list of vaa.Signature and construct signed VAA.
 	agg := make([]bool, len(gs.Keys))
@@ -218,7 +220,7 @@ func (p *Processor) handleObservation(ctx context.Context, m *gossipv1.SignedObs
 		}
 
 		// 2/3+ majority required for VAA to be valid - wait until we have quorum to submit VAA.
-		quorum := CalculateQuorum(len(gs.Keys))
+		quorum := bridge_common.CalculateQuorum(len(gs.Keys))
 
 		p.logger.Info("aggregation state for VAA",
 			zap.String("digest", hash),
@@ -230,66 +232,90 @@ func (p *Processor) handleObservation(ctx context.Context, m *gossipv1.SignedObs
 		)
 
 		if len(sigs) >= quorum && !p.state.vaaSignatures[hash].submitted {
+			s := p.state.vaaSignatures[hash]
+			s.quorum = time.Now()
+			if !s.ourObservation.IsZero() {
+				observationToQuorumSeconds.Observe(s.quorum.Sub(s.ourObservation).Seconds())
+			}
+
 			vaaBytes, err := signed.Marshal()
 			if err != nil {
 				panic(err)
 			}
 
-			// Submit every VAA to Solana for data availability.
-			p.logger.Info("submitting signed VAA to Solana",
-				zap.String("digest", hash),
-				zap.Any("vaa", signed),
-				zap.String("bytes", hex.EncodeToString(vaaBytes)))
-			p.vaaC <- signed
+			// Submit every VAA the Solana program accepts to Solana for data availability. The others are only
+			// logged, and have to be relayed from the logs by whoever needs them.
+			if storedOnSolana(v.Payload.ActionID()) {
+				p.logger.Info("submitting signed VAA to Solana",
+					zap.String("digest", hash),
+					zap.Any("vaa", signed),
+					zap.String("bytes", hex.EncodeToString(vaaBytes)))
+				p.vaaC <- signed
+			} else {
+				p.logger.Info("signed VAA is not stored on Solana",
+					zap.String("digest", hash),
+					zap.Any("vaa", signed),
+					zap.String("bytes", hex.EncodeToString(vaaBytes)))
+			}
+			if p.quorumC != nil {
+				p.quorumC <- signed
+			}
 
 			switch t := v.Payload.(type) {
 			case *vaa.BodyTransfer:
+				p.submitTransfer(ctx, t, signed, hash, vaaBytes)
+			case *vaa.BodyTransferWithPayload:
+				// None of the target chain contracts accept transfers with payload yet, so they aren't submitted
+				// anywhere. The recipient contract has to relay them from the logged VAA bytes once it can.
 				p.state.vaaSignatures[hash].source = t.SourceChain.String()
-				// Depending on the target chain, guardians submit VAAs directly to the chain.
-
-				switch t.TargetChain {
-				case vaa.ChainIDSolana:
-					// No-op.
-				case vaa.ChainIDEthereum:
-					// Ethereum is special because it's expensive, and guardians cannot
-					// be expected to pay the fees. We only submit to Ethereum in devnet mode.
-					p.devnetVAASubmission(ctx, signed, hash)
-				case vaa.ChainIDTerra:
-					go p.terraVAASubmission(ctx, signed, hash)
-				case vaa.ChainIDQtum:
-					go p.qtumVAASubmission(ctx, signed, hash)
-				default:
-					p.logger.Error("unknown target chain ID",
-						zap.String("digest", hash),
-						zap.Any("vaa", signed),
-						zap.String("bytes", hex.EncodeToString(vaaBytes)),
-						zap.Stringer("target_chain", t.TargetChain))
-				}
+				p.logger.Info("transfer with payload needs to be relayed manually, target chain does not support it",
+					zap.String("digest", hash),
+					zap.String("bytes", hex.EncodeToString(vaaBytes)),
+					zap.Stringer("target_chain", t.TargetChain))
+			case *vaa.BodyBatch:
+				// Batches aren't stored on any chain - the Solana program doesn't accept them and no target chain
+				// verifies inclusion proofs yet. They're only available from the logged VAA bytes.
+				p.state.vaaSignatures[hash].source = t.SourceChain.String()
+			case *vaa.BodyMessage:
+				// Messages aren't stored on any chain - it's up to the receiving application to relay them from
+				// the logged VAA bytes.
+				p.state.vaaSignatures[hash].source = t.EmitterChain.String()
 			case *vaa.BodyGuardianSetUpdate:
 				p.state.vaaSignatures[hash].source = "guardian_set_upgrade"
 
 				// A guardian set update is broadcast to every chain that we talk to.
-				p.devnetVAASubmission(ctx, signed, hash)
-				p.terraVAASubmission(ctx, signed, hash)
-				p.qtumVAASubmission(ctx, signed, hash)
+				for _, c := range p.chains.All() {
+					if c.Submitter() != nil {
+						go p.submitVAA(ctx, c, signed, hash)
+					}
+				}
+			case *vaa.BodyBridgePause:
+				p.state.vaaSignatures[hash].source = "bridge_pause"
+				p.applyBridgePause(v, t)
 			case *vaa.BodyContractUpgrade:
 				p.state.vaaSignatures[hash].source = "contract_upgrade"
 
-				switch t.ChainID {
-				case vaa.ChainIDSolana:
-				// Already submitted to Solana.
-				default:
-					p.logger.Error("unsupported target chain for contract upgrade",
-						zap.String("digest", hash),
-						zap.Any("vaa", signed),
-						zap.String("bytes", hex.EncodeToString(vaaBytes)),
-						zap.Uint8("target_chain", t.ChainID))
+				if t.ChainID == vaa.ChainIDSolana {
+					// Already submitted to Solana.
+					break
 				}
+				// None of the other chains' contracts execute contract upgrades yet. Log the VAA, such that it can
+				// be submitted manually once they do.
+				p.logger.Info("contract upgrade needs to be submitted manually, target chain does not support it yet",
+					zap.String("digest", hash),
+					zap.String("bytes", hex.EncodeToString(vaaBytes)),
+					zap.Stringer("target_chain", vaa.ChainID(t.ChainID)))
 			default:
-				panic(fmt.Sprintf("unknown VAA payload type: %+v", v))
+				// Payload types registered outside of the vaa package have no submission path beyond the logged
+				// VAA bytes.
+				p.logger.Warn("no direct submission for payload type",
+					zap.String("digest", hash),
+					zap.Any("vaa", signed),
+					zap.Uint8("action", uint8(v.Payload.ActionID())))
 			}
 
 			p.state.vaaSignatures[hash].submitted = true
+			p.state.vaaSignatures[hash].submittedAt = time.Now()
 		} else {
 			p.logger.Info("quorum not met or already submitted, doing nothing",
 				zap.String("digest", hash))
@@ -302,79 +328,61 @@ func (p *Processor) handleObservation(ctx context.Context, m *gossipv1.SignedObs
 	}
 }
 
-// devnetVAASubmission submits VAA to a local Ethereum devnet. For production, the bridge won't
-// have an Ethereum account and the user retrieves the VAA and submits the transactions themselves.
-func (p *Processor) devnetVAASubmission(ctx context.Context, signed *vaa.VAA, hash string) {
-	if p.devnetMode {
-		observationsDirectSubmissionsTotal.WithLabelValues("ethereum").Inc()
-
-		timeout, cancel := context.WithTimeout(ctx, 15*time.Second)
-		tx, err := devnet.SubmitVAA(timeout, p.devnetEthRPC, signed)
-		cancel()
-		if err != nil {
-			if strings.Contains(err.Error(), "VAA was already executed") {
-				p.logger.Info("VAA already submitted to Ethereum by another node, ignoring",
-					zap.Error(err), zap.String("digest", hash))
-			} else {
-				p.logger.Error("failed to submit VAA to Ethereum",
-					zap.Error(err), zap.String("digest", hash))
-			}
-			return
-		}
-
-		observationsDirectSubmissionSuccessTotal.WithLabelValues("ethereum").Inc()
-		p.logger.Info("VAA submitted to Ethereum", zap.Any("tx", tx), zap.String("digest", hash))
+// storedOnSolana returns whether the Solana program accepts VAAs with the given action.
+func storedOnSolana(a vaa.Action) bool {
+	switch a {
+	case vaa.ActionGuardianSetUpdate, vaa.ActionContractUpgrade, vaa.ActionTransfer:
+		return true
+	default:
+		return false
 	}
 }
 
-// Submit VAA to Terra.
-func (p *Processor) terraVAASubmission(ctx context.Context, signed *vaa.VAA, hash string) {
-	if !p.devnetMode || !p.terraEnabled {
-		p.logger.Warn("ignoring terra VAA submission",
-			zap.String("digest", hash))
+// submitTransfer submits a signed transfer VAA to its target chain.
+func (p *Processor) submitTransfer(ctx context.Context, t *vaa.BodyTransfer, signed *vaa.VAA, hash string, vaaBytes []byte) {
+	p.state.vaaSignatures[hash].source = t.SourceChain.String()
+	// Depending on the target chain, guardians submit VAAs directly to the chain.
+	c := p.chains.Get(t.TargetChain)
+	if c == nil && t.TargetChain.IsKnown() {
+		p.logger.Warn("ignoring VAA submission, target chain is not enabled",
+			zap.String("digest", hash),
+			zap.Stringer("target_chain", t.TargetChain))
 		return
 	}
-
-	observationsDirectSubmissionsTotal.WithLabelValues("terra").Inc()
-
-	tx, err := terra.SubmitVAA(ctx, p.terraLCD, p.terraChainID, p.terraContract, p.terraFeePayer, signed)
-	if err != nil {
-		if strings.Contains(err.Error(), "VaaAlreadyExecuted") {
-			p.logger.Info("VAA already submitted to Terra by another node, ignoring",
-				zap.Error(err), zap.String("digest", hash))
-		} else {
-			p.logger.Error("failed to submit VAA to Terra",
-				zap.Error(err), zap.String("digest", hash))
-		}
+	if c == nil {
+		p.logger.Error("unknown target chain ID",
+			zap.String("digest", hash),
+			zap.Any("vaa", signed),
+			zap.String("bytes", hex.EncodeToString(vaaBytes)),
+			zap.Stringer("target_chain", t.TargetChain))
 		return
 	}
-
-	observationsDirectSubmissionSuccessTotal.WithLabelValues("terra").Inc()
-	p.logger.Info("VAA submitted to Terra", zap.Any("tx", tx), zap.String("digest", hash))
-}
-
-// Submit VAA to Qtum.
-func (p *Processor) qtumVAASubmission(ctx context.Context, signed *vaa.VAA, hash string) {
-	if !p.devnetMode || !p.qtumEnabled {
-		p.logger.Warn("ignoring qtum VAA submission",
-			zap.String("digest", hash))
-		return
+	if c.Submitter() != nil {
+		go p.submitVAA(ctx, c, signed, hash)
 	}
+}
 
-	observationsDirectSubmissionsTotal.WithLabelValues("qtum").Inc()
+// submitVAA submits a VAA to the given chain's VAASubmitter. On most chains, this only happens in devnet mode.
+// For production, the bridge won't have an account and the user retrieves the VAA and submits the transactions
+// themselves.
+func (p *Processor) submitVAA(ctx context.Context, c bridge_common.Chain, signed *vaa.VAA, hash string) {
+	name := c.ID().String()
+	observationsDirectSubmissionsTotal.WithLabelValues(name).Inc()
 
-	tx, err := qtum.SubmitVAA(ctx, p.qtumRPC, p.qtumChainID, p.qtumContract, p.qtumFeePayer, signed)
+	timeout, cancel := context.WithTimeout(ctx, 15*time.Second)
+	tx, err := c.Submitter().SubmitVAA(timeout, signed)
+	cancel()
 	if err != nil {
-		if strings.Contains(err.Error(), "VaaAlreadyExecuted") {
-			p.logger.Info("VAA already submitted to Qtum by another node, ignoring",
-				zap.Error(err), zap.String("digest", hash))
+		if errors.Is(err, bridge_common.ErrVAAAlreadyExecuted) {
+			p.logger.Info("VAA already submitted by another node, ignoring",
+				zap.Stringer("chain", c.ID()), zap.Error(err), zap.String("digest", hash))
 		} else {
-			p.logger.Error("failed to submit VAA to Qtum",
-				zap.Error(err), zap.String("digest", hash))
+			p.logger.Error("failed to submit VAA",
+				zap.Stringer("chain", c.ID()), zap.Error(err), zap.String("digest", hash))
 		}
 		return
 	}
 
-	observationsDirectSubmissionSuccessTotal.WithLabelValues("qtum").Inc()
-	p.logger.Info("VAA submitted to Qtum", zap.Any("tx", tx), zap.String("digest", hash))
+	observationsDirectSubmissionSuccessTotal.WithLabelValues(name).Inc()
+	p.logger.Info("VAA submitted", zap.Stringer("chain", c.ID()), zap.String("tx", tx), zap.String("digest", hash))
 }
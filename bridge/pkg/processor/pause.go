@@ -0,0 +1,235 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+var (
+	chainPaused = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "wormhole_chain_paused",
+			Help: "Whether transfers to and from the chain are paused by governance (1) or not (0)",
+		},
+		[]string{"chain"})
+
+	lockupsRefusedPausedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_lockups_refused_paused_total",
+			Help: "Total number of lockups we deferred signing because their source or target chain is paused",
+		},
+		[]string{"source_chain", "target_chain"})
+
+	lockupsDeferred = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "wormhole_lockups_deferred",
+			Help: "Current number of lockups waiting for their source and target chain to be unpaused",
+		})
+)
+
+func init() {
+	prometheus.MustRegister(chainPaused)
+	prometheus.MustRegister(lockupsRefusedPausedTotal)
+	prometheus.MustRegister(lockupsDeferred)
+}
+
+// pauseState is the state of a chain according to the latest bridge pause VAA that reached quorum.
+type pauseState struct {
+	paused bool
+	// timestamp of the VAA that set the state. VAAs with older timestamps are ignored, such that a stale pause
+	// VAA reaching quorum late can't override a newer one.
+	timestamp time.Time
+}
+
+// supersedes returns whether a VAA setting s replaces the current state c. Of two VAAs with the same timestamp,
+// pausing wins, such that every guardian ends up in the same state regardless of the order they reach quorum in.
+func (s pauseState) supersedes(c pauseState) bool {
+	if s.timestamp.Equal(c.timestamp) {
+		return s.paused || !c.paused
+	}
+	return s.timestamp.After(c.timestamp)
+}
+
+// pauseStateFile is the on-disk format of the pause state.
+type pauseStateFile struct {
+	Chains []pauseStateEntry `json:"chains"`
+	// Deferred are the lockups waiting for their chains to be unpaused.
+	Deferred []*common.ChainLock `json:"deferred,omitempty"`
+}
+
+type pauseStateEntry struct {
+	Chain     vaa.ChainID `json:"chain"`
+	Paused    bool        `json:"paused"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// applyBridgePause updates the pause state of a chain once a bridge pause VAA reached quorum, signs the deferred
+// lockups no longer involving a paused chain, and persists the state if enabled.
+func (p *Processor) applyBridgePause(v *vaa.VAA, t *vaa.BodyBridgePause) {
+	s := pauseState{paused: t.Paused, timestamp: v.Timestamp}
+	if c, ok := p.pausedChains[t.ChainID]; ok && !s.supersedes(c) {
+		p.logger.Warn("ignoring bridge pause VAA superseded by the current state",
+			zap.Stringer("chain", t.ChainID),
+			zap.Bool("paused", t.Paused),
+			zap.Time("timestamp", v.Timestamp),
+			zap.Bool("current_paused", c.paused),
+			zap.Time("current_timestamp", c.timestamp))
+		return
+	}
+
+	p.setPauseState(t.ChainID, s)
+	p.logger.Warn("bridge pause state changed",
+		zap.Stringer("chain", t.ChainID),
+		zap.Bool("paused", t.Paused),
+		zap.Time("timestamp", v.Timestamp))
+
+	if !t.Paused {
+		p.signDeferredLockups()
+	}
+	p.persistPauseState()
+}
+
+// signDeferredLockups signs the deferred lockups whose source and target chain are no longer paused. Every guardian
+// deferred the same lockups, so they reach quorum just like lockups signed as they're observed.
+func (p *Processor) signDeferredLockups() {
+	for e, k := range p.deferredLockups {
+		if p.isPaused(k.SourceChain) || p.isPaused(k.TargetChain) {
+			continue
+		}
+
+		p.logger.Info("signing lockup deferred while a chain was paused",
+			zap.Stringer("source_chain", k.SourceChain),
+			zap.Stringer("target_chain", k.TargetChain),
+			zap.Stringer("txhash", k.TxHash))
+		p.signLockup(k, transferPayload(k))
+		delete(p.deferredLockups, e)
+	}
+	lockupsDeferred.Set(float64(len(p.deferredLockups)))
+}
+
+// persistPauseState saves the pause state, if enabled.
+func (p *Processor) persistPauseState() {
+	if p.pauseStatePath == "" {
+		return
+	}
+	if err := p.savePauseState(); err != nil {
+		p.logger.Error("failed to persist bridge pause state - it will be lost on restart",
+			zap.String("path", p.pauseStatePath), zap.Error(err))
+	}
+}
+
+func (p *Processor) setPauseState(c vaa.ChainID, s pauseState) {
+	p.pausedChains[c] = s
+	if s.paused {
+		chainPaused.WithLabelValues(c.String()).Set(1)
+	} else {
+		chainPaused.WithLabelValues(c.String()).Set(0)
+	}
+}
+
+// loadPauseState restores the pause state persisted by an earlier run. A missing file means no chain was paused.
+func (p *Processor) loadPauseState() error {
+	b, err := ioutil.ReadFile(p.pauseStatePath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read pause state: %w", err)
+	}
+
+	var f pauseStateFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return fmt.Errorf("failed to parse pause state: %w", err)
+	}
+
+	for _, e := range f.Chains {
+		s := pauseState{paused: e.Paused, timestamp: e.Timestamp}
+		if c, ok := p.pausedChains[e.Chain]; ok && !s.supersedes(c) {
+			continue
+		}
+		p.setPauseState(e.Chain, s)
+		if e.Paused {
+			p.logger.Warn("restored bridge pause state", zap.Stringer("chain", e.Chain), zap.Time("timestamp", e.Timestamp))
+		}
+	}
+	for _, k := range f.Deferred {
+		p.deferredLockups[lockupEventID(k)] = k
+	}
+	lockupsDeferred.Set(float64(len(p.deferredLockups)))
+
+	return nil
+}
+
+// savePauseState atomically replaces the persisted pause state.
+func (p *Processor) savePauseState() error {
+	var f pauseStateFile
+	for c, s := range p.pausedChains {
+		f.Chains = append(f.Chains, pauseStateEntry{Chain: c, Paused: s.paused, Timestamp: s.timestamp})
+	}
+	for _, k := range p.deferredLockups {
+		f.Deferred = append(f.Deferred, k)
+	}
+	b, err := json.MarshalIndent(&f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(p.pauseStatePath), filepath.Base(p.pauseStatePath)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write pause state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write pause state: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), p.pauseStatePath); err != nil {
+		return fmt.Errorf("failed to replace pause state: %w", err)
+	}
+
+	return nil
+}
+
+// isPaused returns whether transfers to and from the chain are paused.
+func (p *Processor) isPaused(c vaa.ChainID) bool {
+	return p.pausedChains[c].paused
+}
+
+// lockupPaused returns whether the lockup originates from or targets a paused chain. If so, the lockup is deferred
+// until both chains are unpaused.
+func (p *Processor) lockupPaused(k *common.ChainLock) bool {
+	if !p.isPaused(k.SourceChain) && !p.isPaused(k.TargetChain) {
+		return false
+	}
+
+	e := lockupEventID(k)
+	if _, ok := p.deferredLockups[e]; ok {
+		// Watchers may report the same lockup more than once.
+		return true
+	}
+
+	p.logger.Warn("deferring lockup involving a paused chain until it's unpaused",
+		zap.Stringer("source_chain", k.SourceChain),
+		zap.Stringer("target_chain", k.TargetChain),
+		zap.Stringer("txhash", k.TxHash))
+	lockupsRefusedPausedTotal.WithLabelValues(k.SourceChain.String(), k.TargetChain.String()).Inc()
+
+	p.deferredLockups[e] = k
+	lockupsDeferred.Set(float64(len(p.deferredLockups)))
+	p.persistPauseState()
+	return true
+}
@@ -0,0 +1,133 @@
+package processor
+
+import (
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+func newPauseTestProcessor(path string) *Processor {
+	gk, err := crypto.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+
+	return &Processor{
+		logger:          zap.NewNop(),
+		gk:              gk,
+		gs:              &common.GuardianSet{},
+		sendC:           make(chan []byte, 10),
+		state:           &aggregationState{vaaMap{}, eventMap{}},
+		pausedChains:    map[vaa.ChainID]pauseState{},
+		deferredLockups: map[eventID]*common.ChainLock{},
+		pauseStatePath:  path,
+	}
+}
+
+func pauseVAA(c vaa.ChainID, paused bool, ts time.Time) (*vaa.VAA, *vaa.BodyBridgePause) {
+	t := &vaa.BodyBridgePause{ChainID: c, Paused: paused}
+	return &vaa.VAA{Version: vaa.SupportedVAAVersion, Timestamp: ts, Payload: t}, t
+}
+
+func TestApplyBridgePauseOrdering(t *testing.T) {
+	ts := time.Unix(1605105600, 0)
+	apply := func(p *Processor, paused bool, ts time.Time) {
+		p.applyBridgePause(pauseVAA(vaa.ChainIDEthereum, paused, ts))
+	}
+
+	p := newPauseTestProcessor("")
+	apply(p, true, ts)
+	assert.True(t, p.isPaused(vaa.ChainIDEthereum))
+	assert.False(t, p.isPaused(vaa.ChainIDSolana))
+
+	// Older VAAs are ignored.
+	apply(p, false, ts.Add(-time.Second))
+	assert.True(t, p.isPaused(vaa.ChainIDEthereum))
+
+	apply(p, false, ts.Add(time.Second))
+	assert.False(t, p.isPaused(vaa.ChainIDEthereum))
+
+	// Of two VAAs with the same timestamp, pausing wins regardless of the order they reach quorum in.
+	for _, order := range [][]bool{{true, false}, {false, true}} {
+		p := newPauseTestProcessor("")
+		for _, paused := range order {
+			apply(p, paused, ts)
+		}
+		assert.True(t, p.isPaused(vaa.ChainIDEthereum), "order %v", order)
+	}
+}
+
+func TestPauseStatePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pause.json")
+	ts := time.Unix(1605105600, 0)
+
+	// No state yet.
+	p := newPauseTestProcessor(path)
+	require.NoError(t, p.loadPauseState())
+	assert.Empty(t, p.pausedChains)
+
+	p.applyBridgePause(pauseVAA(vaa.ChainIDEthereum, true, ts))
+	p.applyBridgePause(pauseVAA(vaa.ChainIDTerra, false, ts))
+
+	restarted := newPauseTestProcessor(path)
+	require.NoError(t, restarted.loadPauseState())
+	assert.True(t, restarted.isPaused(vaa.ChainIDEthereum))
+	assert.False(t, restarted.isPaused(vaa.ChainIDTerra))
+
+	// The restored timestamps still apply, so a stale unpause VAA can't resume transfers.
+	restarted.applyBridgePause(pauseVAA(vaa.ChainIDEthereum, false, ts.Add(-time.Second)))
+	assert.True(t, restarted.isPaused(vaa.ChainIDEthereum))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("{"), 0600))
+	assert.Error(t, newPauseTestProcessor(path).loadPauseState())
+}
+
+func TestDeferredLockups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pause.json")
+	ts := time.Unix(1605105600, 0)
+	k := &common.ChainLock{
+		TxHash:      ethcommon.HexToHash("0x06f541f5ecfc43407c31587aa6ac3a689e8960f36dc23c332db5510dfc6a4063"),
+		Timestamp:   ts,
+		SourceChain: vaa.ChainIDSolana,
+		TargetChain: vaa.ChainIDEthereum,
+		TokenChain:  vaa.ChainIDSolana,
+		Amount:      big.NewInt(1000),
+	}
+
+	p := newPauseTestProcessor(path)
+	p.applyBridgePause(pauseVAA(vaa.ChainIDEthereum, true, ts))
+	p.applyBridgePause(pauseVAA(vaa.ChainIDSolana, true, ts))
+	assert.True(t, p.lockupPaused(k))
+	assert.True(t, p.lockupPaused(k), "redelivered lockup")
+	assert.Len(t, p.deferredLockups, 1)
+	assert.Empty(t, p.sendC)
+
+	// Deferred lockups survive a restart.
+	p = newPauseTestProcessor(path)
+	require.NoError(t, p.loadPauseState())
+	require.Len(t, p.deferredLockups, 1)
+
+	// Still involves a paused chain.
+	p.applyBridgePause(pauseVAA(vaa.ChainIDEthereum, false, ts.Add(time.Second)))
+	assert.Len(t, p.deferredLockups, 1)
+	assert.Empty(t, p.sendC)
+
+	p.applyBridgePause(pauseVAA(vaa.ChainIDSolana, false, ts.Add(time.Second)))
+	assert.Empty(t, p.deferredLockups)
+	assert.Len(t, p.sendC, 1)
+
+	p = newPauseTestProcessor(path)
+	require.NoError(t, p.loadPauseState())
+	assert.Empty(t, p.deferredLockups)
+}
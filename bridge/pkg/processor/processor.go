@@ -3,8 +3,8 @@ package processor
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
-	"github.com/certusone/wormhole/bridge/pkg/qtum"
 	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -14,8 +14,8 @@ import (
 	"github.com/certusone/wormhole/bridge/pkg/common"
 	"github.com/certusone/wormhole/bridge/pkg/devnet"
 	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+	"github.com/certusone/wormhole/bridge/pkg/readiness"
 	"github.com/certusone/wormhole/bridge/pkg/supervisor"
-	"github.com/certusone/wormhole/bridge/pkg/terra"
 	"github.com/certusone/wormhole/bridge/pkg/vaa"
 )
 
@@ -29,6 +29,14 @@ type (
 		// Map of signatures seen by guardian. During guardian set updates, this may contain signatures belonging
 		// to either the old or new guardian set.
 		signatures map[ethcommon.Address][]byte
+		// Arrival time of the first valid signature by each guardian.
+		signatureTimes map[ethcommon.Address]time.Time
+		// Time we observed the lockup (or injection) and broadcast our signature.
+		ourObservation time.Time
+		// Time the VAA reached quorum.
+		quorum time.Time
+		// Time the signed VAA was handed off for submission.
+		submittedAt time.Time
 		// Flag set after reaching quorum and submitting the VAA.
 		submitted bool
 		// Flag set by the cleanup service after the settlement timeout has expired and misses were counted.
@@ -48,12 +56,18 @@ type (
 	// aggregationState represents the node's aggregation of guardian signatures.
 	aggregationState struct {
 		vaaSignatures vaaMap
+		// events are the hashes signed for each observed event, used to detect conflicting observations.
+		events eventMap
 	}
 )
 
 type Processor struct {
 	// lockC is a channel of observed chain lockups
 	lockC chan *common.ChainLock
+	// lockBatchC is a channel of lockups confirmed in the same block, signed as a batch
+	lockBatchC chan *common.ChainLockBatch
+	// msgC is a channel of observed message publications
+	msgC chan *common.MessagePublication
 	// setC is a channel of guardian set updates
 	setC chan *common.GuardianSet
 
@@ -62,31 +76,30 @@ type Processor struct {
 	// obsvC is a channel of inbound decoded observations from p2p
 	obsvC chan *gossipv1.SignedObservation
 
-	// vaaC is a channel of VAAs to submit to store on Solana (either as target, or for data availability)
+	// vaaC is a channel of VAAs to submit to store on Solana (either as target, or for data availability).
+	// It only receives VAAs with actions the Solana program accepts.
 	vaaC chan *vaa.VAA
+	// quorumC, if set, receives every VAA that reached quorum, regardless of where it's submitted.
+	quorumC chan *vaa.VAA
 
 	// injectC is a channel of VAAs injected locally.
 	injectC chan *vaa.VAA
 
+	// timelineC is a channel of local queries for VAA timelines (like from the admin service).
+	timelineC chan *TimelineRequest
+
 	// gk is the node's guardian private key
 	gk *ecdsa.PrivateKey
 
-	// devnetMode specified whether to submit transactions to the hardcoded Ethereum devnet
-	devnetMode         bool
-	devnetNumGuardians uint
-	devnetEthRPC       string
+	// chains is the set of connected chains. VAAs are submitted to their target chain's VAASubmitter, if any.
+	chains *common.ChainRegistry
 
-	terraEnabled  bool
-	terraLCD      string
-	terraChainID  string
-	terraContract string
-	terraFeePayer string
+	// gst is updated with the current guardian set, for use by other components (like p2p heartbeat verification)
+	gst *common.GuardianSetState
 
-	qtumEnabled  bool
-	qtumRPC      string
-	qtumChainID  string
-	qtumContract string
-	qtumFeePayer string
+	// devnetMode specified whether to automatically submit a devnet guardian set update
+	devnetMode         bool
+	devnetNumGuardians uint
 
 	logger *zap.Logger
 
@@ -100,63 +113,70 @@ type Processor struct {
 	ourAddr ethcommon.Address
 	// cleanup triggers periodic state cleanup
 	cleanup *time.Ticker
+	// pausedChains is the pause state of chains set by bridge pause VAAs
+	pausedChains map[vaa.ChainID]pauseState
+	// deferredLockups are the lockups involving a paused chain, signed once their chains are unpaused
+	deferredLockups map[eventID]*common.ChainLock
+	// pauseStatePath is the file pausedChains and deferredLockups are persisted to, or empty if they're only kept in
+	// memory.
+	pauseStatePath string
 }
 
 func NewProcessor(
 	ctx context.Context,
 	lockC chan *common.ChainLock,
+	lockBatchC chan *common.ChainLockBatch,
+	msgC chan *common.MessagePublication,
 	setC chan *common.GuardianSet,
 	sendC chan []byte,
 	obsvC chan *gossipv1.SignedObservation,
 	vaaC chan *vaa.VAA,
+	quorumC chan *vaa.VAA,
 	injectC chan *vaa.VAA,
+	timelineC chan *TimelineRequest,
 	gk *ecdsa.PrivateKey,
+	chains *common.ChainRegistry,
+	gst *common.GuardianSetState,
 	devnetMode bool,
 	devnetNumGuardians uint,
-	devnetEthRPC string,
-	terraEnabled bool,
-	terraLCD string,
-	terraChainID string,
-	terraContract string,
-	terraFeePayer string,
-	qtumEnabled bool,
-	qtumRPC string,
-	qtumChainID string,
-	qtumContract string,
-	qtumFeePayer string,
+	pauseStatePath string,
 ) *Processor {
 
 	return &Processor{
 		lockC:              lockC,
+		lockBatchC:         lockBatchC,
+		msgC:               msgC,
 		setC:               setC,
 		sendC:              sendC,
 		obsvC:              obsvC,
 		vaaC:               vaaC,
+		quorumC:            quorumC,
 		injectC:            injectC,
+		timelineC:          timelineC,
 		gk:                 gk,
+		chains:             chains,
+		gst:                gst,
 		devnetMode:         devnetMode,
 		devnetNumGuardians: devnetNumGuardians,
-		devnetEthRPC:       devnetEthRPC,
-
-		terraEnabled:  terraEnabled,
-		terraLCD:      terraLCD,
-		terraChainID:  terraChainID,
-		terraContract: terraContract,
-		terraFeePayer: terraFeePayer,
-
-		qtumEnabled:  qtumEnabled,
-		qtumRPC:      qtumRPC,
-		qtumChainID:  qtumChainID,
-		qtumContract: qtumContract,
-		qtumFeePayer: qtumFeePayer,
 
 		logger:  supervisor.Logger(ctx),
-		state:   &aggregationState{vaaMap{}},
+		state:   &aggregationState{vaaMap{}, eventMap{}},
 		ourAddr: crypto.PubkeyToAddress(gk.PublicKey),
+
+		pausedChains:    map[vaa.ChainID]pauseState{},
+		deferredLockups: map[eventID]*common.ChainLock{},
+		pauseStatePath:  pauseStatePath,
 	}
 }
 
 func (p *Processor) Run(ctx context.Context) error {
+	// Fail rather than silently resume transfers to paused chains if the state can't be restored.
+	if p.pauseStatePath != "" {
+		if err := p.loadPauseState(); err != nil {
+			return err
+		}
+	}
+
 	p.cleanup = time.NewTicker(30 * time.Second)
 
 	for {
@@ -167,6 +187,7 @@ func (p *Processor) Run(ctx context.Context) error {
 			p.logger.Info("guardian set updated",
 				zap.Strings("set", p.gs.KeysAsHexStrings()),
 				zap.Uint32("index", p.gs.Index))
+			p.gst.Set(p.gs)
 
 			// Dev mode guardian set update check (no-op in production)
 			err := p.checkDevModeGuardianSetUpdate(ctx)
@@ -175,16 +196,37 @@ func (p *Processor) Run(ctx context.Context) error {
 			}
 		case k := <-p.lockC:
 			p.handleLockup(ctx, k)
+			readiness.ReportProgress(common.LivenessProcessor)
+		case b := <-p.lockBatchC:
+			p.handleLockBatch(ctx, b)
+			readiness.ReportProgress(common.LivenessProcessor)
+		case m := <-p.msgC:
+			p.handleMessage(ctx, m)
+			readiness.ReportProgress(common.LivenessProcessor)
 		case v := <-p.injectC:
 			p.handleInjection(ctx, v)
+			readiness.ReportProgress(common.LivenessProcessor)
 		case m := <-p.obsvC:
 			p.handleObservation(ctx, m)
+			readiness.ReportProgress(common.LivenessProcessor)
+		case r := <-p.timelineC:
+			p.handleTimelineRequest(r)
 		case <-p.cleanup.C:
 			p.handleCleanup(ctx)
+			// The loop is alive, which is all a quiet network can tell us. A stuck handler blocks the loop and the
+			// tick along with it, so only a processor that is alive but not keeping up needs an explicit check.
+			if !p.obsvBacklogged() {
+				readiness.ReportProgress(common.LivenessProcessor)
+			}
 		}
 	}
 }
 
+// obsvBacklogged returns whether the observation queue is full.
+func (p *Processor) obsvBacklogged() bool {
+	return cap(p.obsvC) > 0 && len(p.obsvC) == cap(p.obsvC)
+}
+
 func (p *Processor) checkDevModeGuardianSetUpdate(ctx context.Context) error {
 	if p.devnetMode {
 		if uint(len(p.gs.Keys)) != p.devnetNumGuardians {
@@ -194,55 +236,45 @@ func (p *Processor) checkDevModeGuardianSetUpdate(ctx context.Context) error {
 				len(p.gs.Keys), p.devnetNumGuardians),
 				zap.Any("v", v))
 
+			eth := p.chains.Get(vaa.ChainIDEthereum)
+			if eth == nil || eth.Submitter() == nil {
+				return errors.New("devnet guardian set change requires an Ethereum submitter")
+			}
+
 			timeout, cancel := context.WithTimeout(ctx, 15*time.Second)
 			defer cancel()
 
-			trx, err := devnet.SubmitVAA(timeout, p.devnetEthRPC, v)
+			trx, err := eth.Submitter().SubmitVAA(timeout, v)
 			if err != nil {
 				// Either Ethereum is not yet up, or another node has already submitted - bail
 				// and let another node handle it. We only check the guardian set on Ethereum,
-				// so we use that to sequence devnet creation for Terra and Solana as well.
+				// so we use that to sequence devnet creation for the other chains as well.
 				return fmt.Errorf("failed to submit Eth devnet guardian set change: %v", err)
 			}
 
-			p.logger.Info("devnet guardian set change submitted to Ethereum", zap.Any("trx", trx), zap.Any("vaa", v))
+			p.logger.Info("devnet guardian set change submitted to Ethereum", zap.String("trx", trx), zap.Any("vaa", v))
 
-			if p.terraEnabled {
-				// Submit to Terra
-				go func() {
-					for {
-						timeout, cancel := context.WithTimeout(ctx, 5*time.Second)
-						trxResponse, err := terra.SubmitVAA(timeout, p.terraLCD, p.terraChainID, p.terraContract, p.terraFeePayer, v)
-						if err != nil {
-							cancel()
-							p.logger.Error("failed to submit Terra devnet guardian set change, retrying", zap.Error(err))
-							time.Sleep(1 * time.Second)
-							continue
-						}
-						cancel()
-						p.logger.Info("devnet guardian set change submitted to Terra", zap.Any("trxResponse", trxResponse), zap.Any("vaa", v))
-						break
-					}
-				}()
-			}
+			for _, c := range p.chains.All() {
+				if c.ID() == vaa.ChainIDEthereum || c.Submitter() == nil {
+					continue
+				}
 
-			if p.qtumEnabled {
-				// Submit to qtum
-				go func() {
+				go func(c common.Chain) {
 					for {
 						timeout, cancel := context.WithTimeout(ctx, 5*time.Second)
-						trxResponse, err := qtum.SubmitVAA(timeout, p.qtumRPC, p.qtumChainID, p.qtumContract, p.qtumFeePayer, v)
+						trx, err := c.Submitter().SubmitVAA(timeout, v)
+						cancel()
 						if err != nil {
-							cancel()
-							p.logger.Error("failed to submit Qtum devnet guardian set change, retrying", zap.Error(err))
+							p.logger.Error("failed to submit devnet guardian set change, retrying",
+								zap.Stringer("chain", c.ID()), zap.Error(err))
 							time.Sleep(1 * time.Second)
 							continue
 						}
-						cancel()
-						p.logger.Info("devnet guardian set change submitted to Qtum", zap.Any("trxResponse", trxResponse), zap.Any("vaa", v))
+						p.logger.Info("devnet guardian set change submitted",
+							zap.Stringer("chain", c.ID()), zap.String("trx", trx), zap.Any("vaa", v))
 						break
 					}
-				}()
+				}(c)
 			}
 
 			// Submit VAA to Solana as well. This is asynchronous and can fail, leading to inconsistent devnet state.
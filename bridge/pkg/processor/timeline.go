@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"sort"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	observationToQuorumSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "wormhole_observation_to_quorum_seconds",
+			Help:    "Time from locally observing a lockup (or injection) to reaching quorum on its VAA",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		})
+	signatureLagSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "wormhole_signature_lag_seconds",
+			Help: "Arrival time of a guardian's signature relative to the VAA reaching quorum, counted on settlement. " +
+				"Negative values are signatures which contributed to quorum.",
+			Buckets: []float64{-30, -10, -5, -2, -1, -0.5, -0.2, -0.1, 0, 0.1, 0.2, 0.5, 1, 2, 5, 10, 30},
+		}, []string{"addr"})
+)
+
+func init() {
+	prometheus.MustRegister(observationToQuorumSeconds)
+	prometheus.MustRegister(signatureLagSeconds)
+}
+
+type (
+	// Timeline is a snapshot of the local aggregation history of a VAA. Times are zero if the event did not
+	// (yet) happen.
+	Timeline struct {
+		// First time the digest was seen, either by our own observation or a remote one.
+		FirstObserved time.Time
+		// Time we observed the lockup (or injection) and broadcast our signature.
+		OurObservation time.Time
+		// Time the VAA reached quorum.
+		Quorum time.Time
+		// Time the signed VAA was handed off for submission.
+		Submitted time.Time
+		// Arrival time of the first valid signature by each guardian, in order of arrival.
+		Signatures []SignatureTime
+		// Human-readable description of the VAA's source.
+		Source string
+		// Index of the guardian set the VAA is aggregated against, if known.
+		GuardianSetIndex *uint32
+	}
+
+	SignatureTime struct {
+		Guardian ethcommon.Address
+		Time     time.Time
+	}
+
+	// TimelineRequest asks the processor for the Timeline of the VAA with the given hex-encoded digest.
+	// The processor sends exactly one response to ResponseC, which is nil if the digest is unknown.
+	// ResponseC must be buffered.
+	TimelineRequest struct {
+		Digest    string
+		ResponseC chan<- *Timeline
+	}
+)
+
+// handleTimelineRequest answers a TimelineRequest from our aggregation state.
+func (p *Processor) handleTimelineRequest(r *TimelineRequest) {
+	s := p.state.vaaSignatures[r.Digest]
+	if s == nil {
+		r.ResponseC <- nil
+		return
+	}
+
+	t := &Timeline{
+		FirstObserved:  s.firstObserved,
+		OurObservation: s.ourObservation,
+		Quorum:         s.quorum,
+		Submitted:      s.submittedAt,
+		Source:         s.source,
+	}
+	if s.gs != nil {
+		idx := s.gs.Index
+		t.GuardianSetIndex = &idx
+	}
+	for addr, at := range s.signatureTimes {
+		t.Signatures = append(t.Signatures, SignatureTime{Guardian: addr, Time: at})
+	}
+	sort.Slice(t.Signatures, func(i, j int) bool { return t.Signatures[i].Time.Before(t.Signatures[j].Time) })
+
+	r.ResponseC <- t
+}
+
+// observeSignatureLag records each guardian's signature arrival relative to quorum. Only VAAs which reached
+// quorum are counted, since there's nothing to measure against otherwise.
+func observeSignatureLag(s *vaaState) {
+	if s.quorum.IsZero() {
+		return
+	}
+
+	for addr, at := range s.signatureTimes {
+		signatureLagSeconds.WithLabelValues(addr.Hex()).Observe(at.Sub(s.quorum).Seconds())
+	}
+}
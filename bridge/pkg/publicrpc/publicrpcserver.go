@@ -22,7 +22,7 @@ func (s *publicrpcServer) GetRawHeartbeats(req *publicrpcv1.GetRawHeartbeatsRequ
 	s.logger.Info("gRPC heartbeat stream opened by client")
 
 	// create a channel and register it for heartbeats
-	receiveChan := make(chan *publicrpcv1.Heartbeat, 50)
+	receiveChan := make(chan *publicrpcv1.HeartbeatEvent, 50)
 	// clientId is the reference to the subscription that we will use for unsubscribing when the client disconnects.
 	clientId := s.rawHeartbeatListeners.subscribeHeartbeats(receiveChan)
 
@@ -33,6 +33,24 @@ func (s *publicrpcServer) GetRawHeartbeats(req *publicrpcv1.GetRawHeartbeatsRequ
 			s.logger.Info("raw heartbeat stream closed by client", zap.Int("clientId", clientId))
 			s.rawHeartbeatListeners.unsubscribeHeartbeats(clientId)
 			return stream.Context().Err()
+		case msg := <-receiveChan:
+			stream.Send(msg.Heartbeat)
+		}
+	}
+}
+
+func (s *publicrpcServer) GetHeartbeats(req *publicrpcv1.GetHeartbeatsRequest, stream publicrpcv1.Publicrpc_GetHeartbeatsServer) error {
+	s.logger.Info("gRPC verified heartbeat stream opened by client")
+
+	receiveChan := make(chan *publicrpcv1.HeartbeatEvent, 50)
+	clientId := s.rawHeartbeatListeners.subscribeHeartbeats(receiveChan)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			s.logger.Info("heartbeat stream closed by client", zap.Int("clientId", clientId))
+			s.rawHeartbeatListeners.unsubscribeHeartbeats(clientId)
+			return stream.Context().Err()
 		case msg := <-receiveChan:
 			stream.Send(msg)
 		}
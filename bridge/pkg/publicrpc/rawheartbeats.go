@@ -26,13 +26,13 @@ func init() {
 // multiplexing to distribute heartbeat messages to all the open connections
 type PublicRawHeartbeatConnections struct {
 	mu     sync.RWMutex
-	subs   map[int]chan<- *publicrpcv1.Heartbeat
+	subs   map[int]chan<- *publicrpcv1.HeartbeatEvent
 	logger *zap.Logger
 }
 
 func HeartbeatStreamMultiplexer(logger *zap.Logger) *PublicRawHeartbeatConnections {
 	ps := &PublicRawHeartbeatConnections{
-		subs:   map[int]chan<- *publicrpcv1.Heartbeat{},
+		subs:   map[int]chan<- *publicrpcv1.HeartbeatEvent{},
 		logger: logger.Named("heartbeatmultiplexer"),
 	}
 	return ps
@@ -50,7 +50,7 @@ func (ps *PublicRawHeartbeatConnections) getUniqueClientId() int {
 }
 
 // subscribeHeartbeats adds a channel to the subscriber map, keyed by arbitary clientId
-func (ps *PublicRawHeartbeatConnections) subscribeHeartbeats(ch chan *publicrpcv1.Heartbeat) int {
+func (ps *PublicRawHeartbeatConnections) subscribeHeartbeats(ch chan *publicrpcv1.HeartbeatEvent) int {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
 
@@ -61,8 +61,8 @@ func (ps *PublicRawHeartbeatConnections) subscribeHeartbeats(ch chan *publicrpcv
 	return clientId
 }
 
-// PublishHeartbeat sends a message to all channels in the subscription map
-func (ps *PublicRawHeartbeatConnections) PublishHeartbeat(msg *publicrpcv1.Heartbeat) {
+// PublishHeartbeat sends a heartbeat event to all channels in the subscription map
+func (ps *PublicRawHeartbeatConnections) PublishHeartbeat(msg *publicrpcv1.HeartbeatEvent) {
 	ps.mu.RLock()
 	defer ps.mu.RUnlock()
 
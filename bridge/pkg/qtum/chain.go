@@ -0,0 +1,73 @@
+package qtum
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/readiness"
+	"github.com/certusone/wormhole/bridge/pkg/supervisor"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+// ReadinessSyncing is the readiness component of the Qtum watcher.
+const ReadinessSyncing readiness.Component = "qtumSyncing"
+
+// Chain is the Qtum chain module.
+type Chain struct {
+	url              string
+	contract         string
+	chainID          string
+	minConfirmations uint64
+	feePayer         string
+
+	// devnetMode enables VAA submission. Production nodes do not yet submit VAAs to Qtum.
+	devnetMode bool
+}
+
+func NewChain(url, contract, chainID string, minConfirmations uint64, feePayer string, devnetMode bool) *Chain {
+	return &Chain{
+		url:              url,
+		contract:         contract,
+		chainID:          chainID,
+		minConfirmations: minConfirmations,
+		feePayer:         feePayer,
+		devnetMode:       devnetMode,
+	}
+}
+
+func (c *Chain) ID() vaa.ChainID {
+	return vaa.ChainIDQtum
+}
+
+func (c *Chain) Name() string {
+	return "qtum"
+}
+
+func (c *Chain) ReadinessComponent() readiness.Component {
+	return ReadinessSyncing
+}
+
+func (c *Chain) Watcher(lockC chan *common.ChainLock, setC chan *common.GuardianSet, stats common.NetworkStatsReporter) supervisor.Runnable {
+	return NewQtumBridgeWatcher(c.url, c.contract, c.chainID, c.minConfirmations, lockC, setC, stats).Run
+}
+
+func (c *Chain) Submitter() common.VAASubmitter {
+	if !c.devnetMode {
+		return nil
+	}
+	return c
+}
+
+func (c *Chain) SubmitVAA(ctx context.Context, v *vaa.VAA) (string, error) {
+	tx, err := SubmitVAA(ctx, c.url, c.chainID, c.contract, c.feePayer, v)
+	if err != nil {
+		if strings.Contains(err.Error(), "VaaAlreadyExecuted") {
+			return "", fmt.Errorf("%w: %v", common.ErrVAAAlreadyExecuted, err)
+		}
+		return "", err
+	}
+
+	return tx, nil
+}
@@ -3,7 +3,6 @@ package qtum
 import (
 	"context"
 	"fmt"
-	"github.com/certusone/wormhole/bridge/pkg/p2p"
 	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
 	"github.com/certusone/wormhole/bridge/pkg/readiness"
 	"github.com/certusone/wormhole/bridge/pkg/supervisor"
@@ -80,6 +79,8 @@ type (
 
 		lockChan chan *common.ChainLock
 		setChan  chan *common.GuardianSet
+
+		stats common.NetworkStatsReporter
 	}
 
 	pendingLock struct {
@@ -88,13 +89,13 @@ type (
 	}
 )
 
-func NewQtumBridgeWatcher(url, bridge, chainID string, minConfirmations uint64, lockEvents chan *common.ChainLock, setEvents chan *common.GuardianSet) *QtumBridgeWatcher {
-	return &QtumBridgeWatcher{url: url, bridge: bridge, chainID: chainID, minConfirmations: minConfirmations, lockChan: lockEvents, setChan: setEvents, pendingLocks: map[eth_common.Hash]*pendingLock{}}
+func NewQtumBridgeWatcher(url, bridge, chainID string, minConfirmations uint64, lockEvents chan *common.ChainLock, setEvents chan *common.GuardianSet, stats common.NetworkStatsReporter) *QtumBridgeWatcher {
+	return &QtumBridgeWatcher{url: url, bridge: bridge, chainID: chainID, minConfirmations: minConfirmations, lockChan: lockEvents, setChan: setEvents, stats: stats, pendingLocks: map[eth_common.Hash]*pendingLock{}}
 }
 
 func (e *QtumBridgeWatcher) Run(ctx context.Context) error {
 	// Initialize gossip metrics (we want to broadcast the address even if we're not yet syncing)
-	p2p.DefaultRegistry.SetNetworkStats(vaa.ChainIDQtum, &gossipv1.Heartbeat_Network{
+	e.stats.SetNetworkStats(&gossipv1.Heartbeat_Network{
 		BridgeAddress: e.bridge,
 	})
 
@@ -249,8 +250,9 @@ func (e *QtumBridgeWatcher) Run(ctx context.Context) error {
 				start := time.Now()
 				logger.Info("processing new header", zap.Int("block", ev.Height))
 				currentQtumHeight.Set(float64(ev.Height))
-				readiness.SetReady(common.ReadinessQtumSyncing)
-				p2p.DefaultRegistry.SetNetworkStats(vaa.ChainIDQtum, &gossipv1.Heartbeat_Network{
+				readiness.SetReady(ReadinessSyncing)
+				readiness.ReportProgress(ReadinessSyncing)
+				e.stats.SetNetworkStats(&gossipv1.Heartbeat_Network{
 					Height:        int64(ev.Height),
 					BridgeAddress: e.bridge,
 				})
@@ -1,5 +1,6 @@
 // package readiness implements a minimal health-checking mechanism for use as k8s readiness probes. It will always
 // return a "ready" state after the conditions have been met for the first time - it's not meant for monitoring.
+// Use the liveness check (ReportProgress and LivenessHandler) for that.
 //
 // Uses a global singleton registry (similar to the Prometheus client's default behavior).
 package readiness
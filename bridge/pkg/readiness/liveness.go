@@ -0,0 +1,138 @@
+package readiness
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Liveness complements readiness: instead of latching once a component is ready, it tracks the last time each
+// component made progress (like a watcher seeing a new block) and reports the node as unhealthy once any component
+// has been stale for longer than its threshold. Unlike the readiness check, it is suitable for monitoring.
+
+var (
+	livenessMu       = sync.Mutex{}
+	livenessRegistry = map[Component]*livenessState{}
+
+	// now is overridden in tests.
+	now = time.Now
+)
+
+type livenessState struct {
+	registered   time.Time
+	lastProgress time.Time
+	// Maximum time between progress reports. If zero, the handler's default threshold applies.
+	threshold time.Duration
+}
+
+// RegisterLivenessComponent registers the given component for liveness checks. If threshold is non-zero, it overrides
+// the default staleness threshold for this component (useful for components that are expected to be slow).
+// Components are given one threshold interval after registration to report their first progress.
+func RegisterLivenessComponent(component Component, threshold time.Duration) {
+	livenessMu.Lock()
+	defer livenessMu.Unlock()
+	if _, ok := livenessRegistry[component]; ok {
+		panic("component already registered")
+	}
+	livenessRegistry[component] = &livenessState{registered: now(), threshold: threshold}
+}
+
+// ReportProgress records that the given component has made progress. Reports for components that were not
+// registered for liveness checks are ignored, such that packages can report progress unconditionally.
+func ReportProgress(component Component) {
+	livenessMu.Lock()
+	defer livenessMu.Unlock()
+	if s, ok := livenessRegistry[component]; ok {
+		s.lastProgress = now()
+	}
+}
+
+// ComponentLiveness is the liveness state of a single component, as reported by LivenessHandler.
+type ComponentLiveness struct {
+	Healthy bool `json:"healthy"`
+	// Last progress report, or null if the component has never reported progress.
+	LastProgress *time.Time `json:"last_progress"`
+	// Seconds since the last progress report (or since registration, if none).
+	StaleSeconds float64 `json:"stale_seconds"`
+	// Staleness threshold applied to this component, in seconds.
+	ThresholdSeconds float64 `json:"threshold_seconds"`
+}
+
+// Liveness is the JSON response body of LivenessHandler.
+type Liveness struct {
+	Healthy    bool                            `json:"healthy"`
+	Components map[Component]ComponentLiveness `json:"components"`
+	// Names of stale components, sorted, for convenience.
+	Stale []Component `json:"stale"`
+}
+
+// CheckLiveness returns the liveness state of all registered components, applying the given
+// default staleness threshold to components without a threshold of their own.
+func CheckLiveness(defaultThreshold time.Duration) *Liveness {
+	livenessMu.Lock()
+	defer livenessMu.Unlock()
+
+	t := now()
+	l := &Liveness{
+		Healthy:    true,
+		Components: make(map[Component]ComponentLiveness, len(livenessRegistry)),
+		Stale:      []Component{},
+	}
+
+	for c, s := range livenessRegistry {
+		threshold := s.threshold
+		if threshold == 0 {
+			threshold = defaultThreshold
+		}
+
+		cl := ComponentLiveness{ThresholdSeconds: threshold.Seconds()}
+
+		since := s.registered
+		if !s.lastProgress.IsZero() {
+			lp := s.lastProgress
+			cl.LastProgress = &lp
+			since = lp
+		}
+
+		stale := t.Sub(since)
+		cl.StaleSeconds = stale.Seconds()
+		cl.Healthy = stale <= threshold
+
+		if !cl.Healthy {
+			l.Healthy = false
+			l.Stale = append(l.Stale, c)
+		}
+
+		l.Components[c] = cl
+	}
+
+	sort.Slice(l.Stale, func(i, j int) bool {
+		return l.Stale[i] < l.Stale[j]
+	})
+
+	return l
+}
+
+// LivenessHandler returns a net/http handler for the liveness check. It returns 200 OK if no component is stale
+// beyond its threshold, or 503 Service Unavailable otherwise. The body is a JSON-encoded Liveness.
+func LivenessHandler(defaultThreshold time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := CheckLiveness(defaultThreshold)
+
+		b, err := json.MarshalIndent(l, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !l.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+
+		_, _ = w.Write(b)
+	}
+}
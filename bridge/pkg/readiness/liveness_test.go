@@ -0,0 +1,92 @@
+package readiness
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLiveness resets the global liveness registry and returns a function that advances the mock clock.
+func setupLiveness(t *testing.T) func(d time.Duration) {
+	t.Helper()
+
+	ts := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return ts }
+	livenessRegistry = map[Component]*livenessState{}
+
+	t.Cleanup(func() {
+		now = time.Now
+		livenessRegistry = map[Component]*livenessState{}
+	})
+
+	return func(d time.Duration) {
+		ts = ts.Add(d)
+	}
+}
+
+func TestLivenessStaleness(t *testing.T) {
+	advance := setupLiveness(t)
+
+	RegisterLivenessComponent("fast", 0)
+	RegisterLivenessComponent("slow", 10*time.Minute)
+
+	// Components get one threshold interval after registration to report progress.
+	l := CheckLiveness(time.Minute)
+	assert.True(t, l.Healthy)
+	assert.Nil(t, l.Components["fast"].LastProgress)
+
+	advance(2 * time.Minute)
+	l = CheckLiveness(time.Minute)
+	assert.False(t, l.Healthy)
+	assert.Equal(t, []Component{"fast"}, l.Stale)
+	assert.True(t, l.Components["slow"].Healthy)
+	assert.Equal(t, 600.0, l.Components["slow"].ThresholdSeconds)
+
+	ReportProgress("fast")
+	l = CheckLiveness(time.Minute)
+	assert.True(t, l.Healthy)
+	require.NotNil(t, l.Components["fast"].LastProgress)
+	assert.Equal(t, 0.0, l.Components["fast"].StaleSeconds)
+
+	advance(11 * time.Minute)
+	ReportProgress("fast")
+	l = CheckLiveness(time.Minute)
+	assert.False(t, l.Healthy)
+	assert.Equal(t, []Component{"slow"}, l.Stale)
+}
+
+func TestReportProgressUnregistered(t *testing.T) {
+	setupLiveness(t)
+
+	// Must not panic or implicitly register the component.
+	ReportProgress("unknown")
+	assert.Empty(t, CheckLiveness(time.Minute).Components)
+}
+
+func TestLivenessHandler(t *testing.T) {
+	advance := setupLiveness(t)
+
+	RegisterLivenessComponent("watcher", 0)
+	h := LivenessHandler(time.Minute)
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	advance(time.Hour)
+	rec = httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var l Liveness
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &l))
+	assert.False(t, l.Healthy)
+	assert.False(t, l.Components["watcher"].Healthy)
+	assert.Equal(t, 3600.0, l.Components["watcher"].StaleSeconds)
+}
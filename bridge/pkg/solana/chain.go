@@ -0,0 +1,48 @@
+package solana
+
+import (
+	"github.com/dfuse-io/solana-go"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/readiness"
+	"github.com/certusone/wormhole/bridge/pkg/supervisor"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+// ReadinessSyncing is the readiness component of the Solana watcher.
+const ReadinessSyncing readiness.Component = "solanaSyncing"
+
+// Chain is the Solana chain module.
+//
+// It has no VAASubmitter - every VAA, regardless of its target chain, is stored on Solana for data availability
+// by the SolanaVAASubmitter, which runs independently of the chain registry.
+type Chain struct {
+	wsUrl  string
+	rpcUrl string
+	bridge solana.PublicKey
+}
+
+func NewChain(wsUrl, rpcUrl string, bridge solana.PublicKey) *Chain {
+	return &Chain{wsUrl: wsUrl, rpcUrl: rpcUrl, bridge: bridge}
+}
+
+func (c *Chain) ID() vaa.ChainID {
+	return vaa.ChainIDSolana
+}
+
+func (c *Chain) Name() string {
+	return "sol"
+}
+
+func (c *Chain) ReadinessComponent() readiness.Component {
+	return ReadinessSyncing
+}
+
+func (c *Chain) Watcher(lockC chan *common.ChainLock, setC chan *common.GuardianSet, stats common.NetworkStatsReporter) supervisor.Runnable {
+	// Solana does not report guardian set changes - Ethereum is the source of truth.
+	return NewSolanaWatcher(c.wsUrl, c.rpcUrl, c.bridge, lockC, stats).Run
+}
+
+func (c *Chain) Submitter() common.VAASubmitter {
+	return nil
+}
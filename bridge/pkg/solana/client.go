@@ -6,8 +6,8 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/certusone/wormhole/bridge/pkg/common"
-	"github.com/certusone/wormhole/bridge/pkg/p2p"
 	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
+	"github.com/certusone/wormhole/bridge/pkg/readiness"
 	"github.com/certusone/wormhole/bridge/pkg/supervisor"
 	"github.com/certusone/wormhole/bridge/pkg/vaa"
 	"github.com/dfuse-io/solana-go"
@@ -25,6 +25,7 @@ type SolanaWatcher struct {
 	wsUrl     string
 	rpcUrl    string
 	lockEvent chan *common.ChainLock
+	stats     common.NetworkStatsReporter
 }
 
 var (
@@ -63,14 +64,14 @@ func init() {
 	prometheus.MustRegister(queryLatency)
 }
 
-func NewSolanaWatcher(wsUrl, rpcUrl string, bridgeAddress solana.PublicKey, lockEvents chan *common.ChainLock) *SolanaWatcher {
-	return &SolanaWatcher{bridge: bridgeAddress, wsUrl: wsUrl, rpcUrl: rpcUrl, lockEvent: lockEvents}
+func NewSolanaWatcher(wsUrl, rpcUrl string, bridgeAddress solana.PublicKey, lockEvents chan *common.ChainLock, stats common.NetworkStatsReporter) *SolanaWatcher {
+	return &SolanaWatcher{bridge: bridgeAddress, wsUrl: wsUrl, rpcUrl: rpcUrl, lockEvent: lockEvents, stats: stats}
 }
 
 func (s *SolanaWatcher) Run(ctx context.Context) error {
 	// Initialize gossip metrics (we want to broadcast the address even if we're not yet syncing)
 	bridgeAddr := base58.Encode(s.bridge[:])
-	p2p.DefaultRegistry.SetNetworkStats(vaa.ChainIDSolana, &gossipv1.Heartbeat_Network{
+	s.stats.SetNetworkStats(&gossipv1.Heartbeat_Network{
 		BridgeAddress: bridgeAddr,
 	})
 
@@ -82,6 +83,8 @@ func (s *SolanaWatcher) Run(ctx context.Context) error {
 		timer := time.NewTicker(time.Second * 5)
 		defer timer.Stop()
 
+		var lastSlot uint64
+
 		for {
 			select {
 			case <-ctx.Done():
@@ -100,7 +103,11 @@ func (s *SolanaWatcher) Run(ctx context.Context) error {
 						return
 					}
 					currentSolanaHeight.Set(float64(slot))
-					p2p.DefaultRegistry.SetNetworkStats(vaa.ChainIDSolana, &gossipv1.Heartbeat_Network{
+					if uint64(slot) > lastSlot {
+						lastSlot = uint64(slot)
+						readiness.ReportProgress(ReadinessSyncing)
+					}
+					s.stats.SetNetworkStats(&gossipv1.Heartbeat_Network{
 						Height:        int64(slot),
 						BridgeAddress: bridgeAddr,
 					})
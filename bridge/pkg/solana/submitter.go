@@ -17,7 +17,6 @@ import (
 	agentv1 "github.com/certusone/wormhole/bridge/pkg/proto/agent/v1"
 	"github.com/certusone/wormhole/bridge/pkg/readiness"
 
-	"github.com/certusone/wormhole/bridge/pkg/common"
 	"github.com/certusone/wormhole/bridge/pkg/supervisor"
 	"github.com/certusone/wormhole/bridge/pkg/vaa"
 )
@@ -75,7 +74,7 @@ func (e *SolanaVAASubmitter) Run(ctx context.Context) error {
 		solanaConnectionErrors.WithLabelValues("get_balance_error").Inc()
 		return fmt.Errorf("failed to get balance: %v", err)
 	}
-	readiness.SetReady(common.ReadinessSolanaSyncing)
+	readiness.SetReady(ReadinessSyncing)
 	logger.Info("account balance", zap.Uint64("lamports", balance.Balance))
 
 	// Periodically request the balance for monitoring
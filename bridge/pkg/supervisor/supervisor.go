@@ -78,6 +78,10 @@ type supervisor struct {
 	// pReq is an interface channel to the lifecycle processor of the supervisor.
 	pReq chan *processorRequest
 
+	// stats keeps per-DN restart statistics. Unlike nodes, which get recreated whenever their parent restarts,
+	// stats persist for the entire lifetime of the supervisor.
+	stats map[string]*nodeStats
+
 	// propagate panics, ie. don't catch them.
 	propagatePanic bool
 }
@@ -100,6 +104,7 @@ func New(ctx context.Context, logger *zap.Logger, rootRunnable Runnable, opts ..
 		logger:  logger,
 		ilogger: logger.Named("supervisor"),
 		pReq:    make(chan *processorRequest),
+		stats:   make(map[string]*nodeStats),
 	}
 
 	for _, o := range opts {
@@ -198,6 +198,7 @@ func (s *supervisor) processDied(r *processorRequestDied) {
 	}
 
 	s.ilogger.Error("Runnable died", zap.String("dn", n.dn()), zap.Error(err))
+	s.statsByDN(n.dn()).recordError(err)
 	// Mark as dead.
 	n.state = nodeStateDead
 
@@ -377,6 +378,7 @@ func (s *supervisor) processGC() {
 
 		// Prepare node for rescheduling - remove its children, reset its state to new.
 		n.reset()
+		s.statsByDN(dn).recordRestart(dn, bo)
 		s.ilogger.Info("rescheduling supervised node", zap.String("dn", dn), zap.Duration("backoff", bo))
 
 		// Reschedule node runnable to run after backoff.
@@ -0,0 +1,117 @@
+package supervisor
+
+// Introspection of the supervision tree, for debugging and monitoring.
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	runnableRestarts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wormhole_supervisor_runnable_restarts_total",
+			Help: "Total number of times a supervised runnable was restarted, grouped by DN",
+		}, []string{"dn"})
+)
+
+func init() {
+	prometheus.MustRegister(runnableRestarts)
+}
+
+// nodeStats are restart statistics for a given DN.
+type nodeStats struct {
+	restarts      uint64
+	lastError     error
+	lastErrorTime time.Time
+	backoff       time.Duration
+}
+
+func (st *nodeStats) recordError(err error) {
+	st.lastError = err
+	st.lastErrorTime = time.Now()
+}
+
+func (st *nodeStats) recordRestart(dn string, bo time.Duration) {
+	st.restarts += 1
+	st.backoff = bo
+	runnableRestarts.WithLabelValues(dn).Inc()
+}
+
+// statsByDN returns the stats for a DN, creating them if needed. Must be called with the supervisor lock taken.
+func (s *supervisor) statsByDN(dn string) *nodeStats {
+	st, ok := s.stats[dn]
+	if !ok {
+		st = &nodeStats{}
+		s.stats[dn] = st
+	}
+	return st
+}
+
+// NodeStatus is a point-in-time view of a node in the supervision tree.
+type NodeStatus struct {
+	// DN is the distinguished name of the node, like root.foo.bar.
+	DN string `json:"dn"`
+	// State is the current state of the node's runnable, like NODE_STATE_HEALTHY.
+	State string `json:"state"`
+	// Restarts is the number of times the node has been restarted, either because it died or because a related
+	// runnable died.
+	Restarts uint64 `json:"restarts"`
+	// LastError is the error the node's runnable last died with, if any.
+	LastError string `json:"last_error,omitempty"`
+	// LastErrorTime is the time the node's runnable last died, if ever.
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+	// Backoff is the delay applied before the most recent restart.
+	Backoff time.Duration `json:"backoff"`
+}
+
+// Snapshot returns the status of all nodes in the supervision tree, in depth-first order with
+// children sorted by name.
+func (s *supervisor) Snapshot() []NodeStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res []NodeStatus
+	var walk func(n *node)
+	walk = func(n *node) {
+		dn := n.dn()
+		ns := NodeStatus{
+			DN:    dn,
+			State: n.state.String(),
+		}
+		if st, ok := s.stats[dn]; ok {
+			ns.Restarts = st.restarts
+			ns.Backoff = st.backoff
+			if st.lastError != nil {
+				ns.LastError = st.lastError.Error()
+				t := st.lastErrorTime
+				ns.LastErrorTime = &t
+			}
+		}
+		res = append(res, ns)
+
+		names := make([]string, 0, len(n.children))
+		for name := range n.children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			walk(n.children[name])
+		}
+	}
+	walk(s.root)
+
+	return res
+}
+
+// Snapshot returns the status of all nodes in the supervision tree that the calling runnable is part of.
+func Snapshot(ctx context.Context) []NodeStatus {
+	sup, ok := ctx.Value(supervisorKey).(*supervisor)
+	if !ok {
+		panic("supervisor function called from non-runnable context")
+	}
+	return sup.Snapshot()
+}
@@ -551,3 +551,66 @@ func ExampleNew() {
 	// root.child will close this channel.
 	<-childC
 }
+
+func TestSnapshot(t *testing.T) {
+	one := newRC()
+	two := newRC()
+
+	log, _ := zap.NewDevelopment()
+	ctx, ctxC := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ctxC()
+	s := New(ctx, log, func(ctx context.Context) error {
+		err := RunGroup(ctx, map[string]Runnable{
+			"one": one.runnable(),
+			"two": two.runnable(),
+		})
+		if err != nil {
+			return err
+		}
+		Signal(ctx, SignalHealthy)
+		Signal(ctx, SignalDone)
+		return nil
+	}, WithPropagatePanic)
+	s.waitSettleError(ctx, t)
+
+	one.becomeHealthy()
+	two.becomeHealthy()
+	s.waitSettleError(ctx, t)
+
+	byDN := func() map[string]NodeStatus {
+		res := make(map[string]NodeStatus)
+		for _, ns := range s.Snapshot() {
+			res[ns.DN] = ns
+		}
+		return res
+	}
+
+	st := byDN()
+	if len(st) != 3 {
+		t.Fatalf("expected 3 nodes, got %+v", st)
+	}
+	if want, got := "NODE_STATE_HEALTHY", st["root.one"].State; want != got {
+		t.Errorf("root.one state: wanted %s, got %s", want, got)
+	}
+	if st["root.two"].Restarts != 0 || st["root.two"].LastError != "" {
+		t.Errorf("root.two has unexpected restart stats: %+v", st["root.two"])
+	}
+
+	// Kill off two, both should be restarted, with only two having an error.
+	two.die()
+	s.waitSettleError(ctx, t)
+
+	st = byDN()
+	if st["root.two"].Restarts != 1 || st["root.two"].LastError == "" || st["root.two"].LastErrorTime == nil {
+		t.Errorf("root.two has unexpected restart stats: %+v", st["root.two"])
+	}
+	if st["root.two"].Backoff == 0 {
+		t.Errorf("root.two was restarted without backoff")
+	}
+	if st["root.one"].Restarts != 1 || st["root.one"].LastError != "" {
+		t.Errorf("root.one has unexpected restart stats: %+v", st["root.one"])
+	}
+	if st["root"].Restarts != 0 {
+		t.Errorf("root has unexpected restart stats: %+v", st["root"])
+	}
+}
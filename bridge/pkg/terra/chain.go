@@ -0,0 +1,73 @@
+package terra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/certusone/wormhole/bridge/pkg/common"
+	"github.com/certusone/wormhole/bridge/pkg/readiness"
+	"github.com/certusone/wormhole/bridge/pkg/supervisor"
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+// ReadinessSyncing is the readiness component of the Terra watcher.
+const ReadinessSyncing readiness.Component = "terraSyncing"
+
+// Chain is the Terra chain module.
+type Chain struct {
+	urlWS    string
+	urlLCD   string
+	chainID  string
+	contract string
+	feePayer string
+
+	// devnetMode enables VAA submission. Production nodes do not yet submit VAAs to Terra.
+	devnetMode bool
+}
+
+func NewChain(urlWS, urlLCD, chainID, contract, feePayer string, devnetMode bool) *Chain {
+	return &Chain{
+		urlWS:      urlWS,
+		urlLCD:     urlLCD,
+		chainID:    chainID,
+		contract:   contract,
+		feePayer:   feePayer,
+		devnetMode: devnetMode,
+	}
+}
+
+func (c *Chain) ID() vaa.ChainID {
+	return vaa.ChainIDTerra
+}
+
+func (c *Chain) Name() string {
+	return "terra"
+}
+
+func (c *Chain) ReadinessComponent() readiness.Component {
+	return ReadinessSyncing
+}
+
+func (c *Chain) Watcher(lockC chan *common.ChainLock, setC chan *common.GuardianSet, stats common.NetworkStatsReporter) supervisor.Runnable {
+	return NewTerraBridgeWatcher(c.urlWS, c.urlLCD, c.contract, lockC, setC, stats).Run
+}
+
+func (c *Chain) Submitter() common.VAASubmitter {
+	if !c.devnetMode {
+		return nil
+	}
+	return c
+}
+
+func (c *Chain) SubmitVAA(ctx context.Context, v *vaa.VAA) (string, error) {
+	tx, err := SubmitVAA(ctx, c.urlLCD, c.chainID, c.contract, c.feePayer, v)
+	if err != nil {
+		if strings.Contains(err.Error(), "VaaAlreadyExecuted") {
+			return "", fmt.Errorf("%w: %v", common.ErrVAAAlreadyExecuted, err)
+		}
+		return "", err
+	}
+
+	return tx.TxHash, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
-	"github.com/certusone/wormhole/bridge/pkg/p2p"
 	gossipv1 "github.com/certusone/wormhole/bridge/pkg/proto/gossip/v1"
 	"io/ioutil"
 	"math/big"
@@ -33,6 +32,8 @@ type (
 
 		lockChan chan *common.ChainLock
 		setChan  chan *common.GuardianSet
+
+		stats common.NetworkStatsReporter
 	}
 )
 
@@ -78,13 +79,13 @@ type clientRequest struct {
 }
 
 // NewTerraBridgeWatcher creates a new terra bridge watcher
-func NewTerraBridgeWatcher(urlWS string, urlLCD string, bridge string, lockEvents chan *common.ChainLock, setEvents chan *common.GuardianSet) *BridgeWatcher {
-	return &BridgeWatcher{urlWS: urlWS, urlLCD: urlLCD, bridge: bridge, lockChan: lockEvents, setChan: setEvents}
+func NewTerraBridgeWatcher(urlWS string, urlLCD string, bridge string, lockEvents chan *common.ChainLock, setEvents chan *common.GuardianSet, stats common.NetworkStatsReporter) *BridgeWatcher {
+	return &BridgeWatcher{urlWS: urlWS, urlLCD: urlLCD, bridge: bridge, lockChan: lockEvents, setChan: setEvents, stats: stats}
 }
 
 // Run is the main Terra Bridge run cycle
 func (e *BridgeWatcher) Run(ctx context.Context) error {
-	p2p.DefaultRegistry.SetNetworkStats(vaa.ChainIDTerra, &gossipv1.Heartbeat_Network{
+	e.stats.SetNetworkStats(&gossipv1.Heartbeat_Network{
 		BridgeAddress: e.bridge,
 	})
 
@@ -122,7 +123,7 @@ func (e *BridgeWatcher) Run(ctx context.Context) error {
 	}
 	logger.Info("subscribed to new transaction events")
 
-	readiness.SetReady(common.ReadinessTerraSyncing)
+	readiness.SetReady(ReadinessSyncing)
 
 	go func() {
 		t := time.NewTicker(5 * time.Second)
@@ -130,6 +131,8 @@ func (e *BridgeWatcher) Run(ctx context.Context) error {
 			Timeout: time.Second * 5,
 		}
 
+		var lastHeight int64
+
 		for {
 			<-t.C
 
@@ -152,7 +155,11 @@ func (e *BridgeWatcher) Run(ctx context.Context) error {
 			latestBlock := gjson.Get(blockJSON, "block.header.height")
 			logger.Info("current Terra height", zap.Int64("block", latestBlock.Int()))
 			currentTerraHeight.Set(float64(latestBlock.Int()))
-			p2p.DefaultRegistry.SetNetworkStats(vaa.ChainIDTerra, &gossipv1.Heartbeat_Network{
+			if latestBlock.Int() > lastHeight {
+				lastHeight = latestBlock.Int()
+				readiness.ReportProgress(ReadinessSyncing)
+			}
+			e.stats.SetNetworkStats(&gossipv1.Heartbeat_Network{
 				Height:        latestBlock.Int(),
 				BridgeAddress: e.bridge,
 			})
@@ -215,6 +222,12 @@ func (e *BridgeWatcher) Run(ctx context.Context) error {
 					logger.Error("cannot decode hex", zap.String("value", token.String()))
 					continue
 				}
+				logger.Info("decoded Terra token lock addresses",
+					zap.String("txHash", txHash.String()),
+					zap.String("sender", senderAddress.NativeString(vaa.ChainIDTerra)),
+					zap.String("recipient", recipientAddress.NativeString(vaa.ChainID(uint8(targetChain.Uint())))),
+					zap.String("token", tokenAddress.NativeString(vaa.ChainID(uint8(tokenChain.Uint())))),
+				)
 				txHashValue, err := StringToHash(txHash.String())
 				if err != nil {
 					logger.Error("cannot decode hex", zap.String("value", txHash.String()))
@@ -0,0 +1,246 @@
+package vaa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mr-tron/base58"
+	"github.com/tendermint/tendermint/libs/bech32"
+)
+
+// AddressCodec converts addresses between their 32-byte VAA representation and a chain's native string form.
+type AddressCodec struct {
+	// Encode returns the native form of the address, or an error if it isn't a valid address on the chain.
+	Encode func(a Address) (string, error)
+	// Decode parses the native form of an address.
+	Decode func(s string) (Address, error)
+}
+
+// ErrAddressPadding means an address shorter than 32 bytes on its chain isn't left-padded with zeros.
+var ErrAddressPadding = errors.New("non-zero address padding")
+
+const (
+	// terraBech32Prefix is the human-readable part of Terra account and contract addresses.
+	terraBech32Prefix = "terra"
+	// qtumPubKeyHashMainnet and qtumPubKeyHashTestnet are the version bytes of base58 Qtum addresses.
+	qtumPubKeyHashMainnet = 0x3a
+	qtumPubKeyHashTestnet = 0x78
+)
+
+// addressCodecs maps chain IDs to the codec for their native address format. Chain modules for chains
+// not known to this package can add theirs using RegisterAddressCodec.
+var addressCodecs = map[ChainID]AddressCodec{
+	ChainIDSolana:   {Encode: encodeSolanaAddress, Decode: decodeSolanaAddress},
+	ChainIDEthereum: {Encode: encodeEthereumAddress, Decode: decodeEthereumAddress},
+	ChainIDTerra:    {Encode: encodeTerraAddress, Decode: decodeTerraAddress},
+	ChainIDQtum:     {Encode: encodeQtumAddress, Decode: decodeQtumAddress},
+}
+
+// hexAddressCodec is used for chains without a registered codec.
+var hexAddressCodec = AddressCodec{Encode: encodeHexAddress, Decode: decodeHexAddress}
+
+// RegisterAddressCodec sets the native address format for a chain. It is not thread safe and must only be
+// called from init functions.
+func RegisterAddressCodec(c ChainID, codec AddressCodec) {
+	if codec.Encode == nil || codec.Decode == nil {
+		panic(fmt.Sprintf("incomplete address codec for chain %d", c))
+	}
+	addressCodecs[c] = codec
+}
+
+func addressCodec(c ChainID) AddressCodec {
+	if codec, ok := addressCodecs[c]; ok {
+		return codec
+	}
+	return hexAddressCodec
+}
+
+// FormatAddress returns the native form of the address on the given chain. Addresses on chains without a
+// registered codec are hex-encoded.
+func FormatAddress(c ChainID, a Address) (string, error) {
+	return addressCodec(c).Encode(a)
+}
+
+// ParseAddress parses the native form of an address on the given chain. Addresses on chains without a
+// registered codec are expected to be 32 hex-encoded bytes.
+func ParseAddress(c ChainID, s string) (Address, error) {
+	a, err := addressCodec(c).Decode(s)
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid %s address %q: %w", c, s, err)
+	}
+	return a, nil
+}
+
+// ParseHexAddress parses the chain-independent form of an address, 32 hex-encoded bytes as returned by
+// Address.String.
+func ParseHexAddress(s string) (Address, error) {
+	a, err := decodeHexAddress(s)
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid address %q: %w", s, err)
+	}
+	return a, nil
+}
+
+// NativeString returns the native form of the address on the given chain for display purposes, like logs.
+// Unlike FormatAddress, it falls back to hex if the address isn't valid on the chain.
+func (a Address) NativeString(c ChainID) string {
+	s, err := FormatAddress(c, a)
+	if err != nil {
+		return a.String()
+	}
+	return s
+}
+
+// unpad20 returns the 20-byte address which the address is a left-padded copy of.
+func unpad20(a Address) ([]byte, error) {
+	for _, b := range a[:12] {
+		if b != 0 {
+			return nil, ErrAddressPadding
+		}
+	}
+	return a[12:], nil
+}
+
+// pad20 left-pads a 20-byte address to 32 bytes.
+func pad20(b []byte) (Address, error) {
+	var a Address
+	if len(b) != 20 {
+		return a, fmt.Errorf("expected 20 bytes, got %d", len(b))
+	}
+	copy(a[12:], b)
+	return a, nil
+}
+
+func encodeHexAddress(a Address) (string, error) {
+	return a.String(), nil
+}
+
+func decodeHexAddress(s string) (Address, error) {
+	var a Address
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return a, err
+	}
+	if len(b) != len(a) {
+		return a, fmt.Errorf("expected %d bytes, got %d", len(a), len(b))
+	}
+	copy(a[:], b)
+	return a, nil
+}
+
+// Solana addresses are base58-encoded 32 byte public keys.
+
+func encodeSolanaAddress(a Address) (string, error) {
+	return base58.Encode(a[:]), nil
+}
+
+func decodeSolanaAddress(s string) (Address, error) {
+	var a Address
+	b, err := base58.Decode(s)
+	if err != nil {
+		return a, err
+	}
+	if len(b) != len(a) {
+		return a, fmt.Errorf("expected %d base58-encoded bytes, got %d", len(a), len(b))
+	}
+	copy(a[:], b)
+	return a, nil
+}
+
+// Ethereum addresses are 20 bytes, hex-encoded with a mixed-case checksum (EIP-55).
+
+func encodeEthereumAddress(a Address) (string, error) {
+	b, err := unpad20(a)
+	if err != nil {
+		return "", err
+	}
+	return common.BytesToAddress(b).Hex(), nil
+}
+
+func decodeEthereumAddress(s string) (Address, error) {
+	if !strings.HasPrefix(s, "0x") || !common.IsHexAddress(s) {
+		return Address{}, errors.New("expected 0x-prefixed 20 byte hex address")
+	}
+	addr := common.HexToAddress(s)
+	// Only verify the checksum of mixed-case addresses, all lower- or uppercase ones don't have any.
+	if s[2:] != strings.ToLower(s[2:]) && s[2:] != strings.ToUpper(s[2:]) && s != addr.Hex() {
+		return Address{}, errors.New("invalid checksum")
+	}
+	return pad20(addr.Bytes())
+}
+
+// Terra addresses are 20 bytes, bech32-encoded with the "terra" prefix.
+
+func encodeTerraAddress(a Address) (string, error) {
+	b, err := unpad20(a)
+	if err != nil {
+		return "", err
+	}
+	return bech32.ConvertAndEncode(terraBech32Prefix, b)
+}
+
+func decodeTerraAddress(s string) (Address, error) {
+	hrp, b, err := bech32.DecodeAndConvert(s)
+	if err != nil {
+		return Address{}, err
+	}
+	if hrp != terraBech32Prefix {
+		return Address{}, fmt.Errorf("expected prefix %q, got %q", terraBech32Prefix, hrp)
+	}
+	return pad20(b)
+}
+
+// Qtum addresses are 20 bytes. Contracts are hex-encoded without prefix, while accounts are usually
+// base58check-encoded with a network-specific version byte. We render the hex form, which doesn't
+// depend on the network.
+
+func encodeQtumAddress(a Address) (string, error) {
+	b, err := unpad20(a)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func decodeQtumAddress(s string) (Address, error) {
+	if h := strings.TrimPrefix(s, "0x"); len(h) == 40 {
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return Address{}, err
+		}
+		return pad20(b)
+	}
+
+	b, version, err := base58CheckDecode(s)
+	if err != nil {
+		return Address{}, err
+	}
+	if version != qtumPubKeyHashMainnet && version != qtumPubKeyHashTestnet {
+		return Address{}, fmt.Errorf("unknown address version %#x", version)
+	}
+	return pad20(b)
+}
+
+// base58CheckDecode decodes a base58check string, which is a version byte and payload followed by the first four
+// bytes of their double SHA-256 hash.
+func base58CheckDecode(s string) (payload []byte, version byte, err error) {
+	b, err := base58.Decode(s)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(b) < 5 {
+		return nil, 0, errors.New("invalid base58check format")
+	}
+	data, checksum := b[:len(b)-4], b[len(b)-4:]
+	h := sha256.Sum256(data)
+	h = sha256.Sum256(h[:])
+	if !bytes.Equal(h[:4], checksum) {
+		return nil, 0, errors.New("invalid base58check checksum")
+	}
+	return data[1:], data[0], nil
+}
@@ -0,0 +1,129 @@
+package vaa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHexAddress(t *testing.T, s string) Address {
+	a, err := decodeHexAddress(s)
+	require.NoError(t, err)
+	return a
+}
+
+func base58CheckEncode(payload []byte, version byte) string {
+	b := append([]byte{version}, payload...)
+	h := sha256.Sum256(b)
+	h = sha256.Sum256(h[:])
+	return base58.Encode(append(b, h[:4]...))
+}
+
+func TestAddressCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		chain  ChainID
+		native string
+		hex    string
+	}{
+		{ChainIDSolana, "Bridge1p5gheXUvJ6jGWGeCsgPKgnE3YgdGKRVCMY9o", "02c806312cbe5b79ef8aa6c17e3f423d8fdfe1d46909fb1f6cdf65ee8e2e6faa"},
+		{ChainIDEthereum, "0x90F8bf6A479f320ead074411a4B0e7944Ea8c9C1", "00000000000000000000000090f8bf6a479f320ead074411a4b0e7944ea8c9c1"},
+		{ChainIDTerra, "terra1x46rqay4d3cssq8gxxvqz8xt6nwlz4td20k38v", "00000000000000000000000035743074956c710800e83198011ccbd4ddf1556d"},
+		{ChainIDQtum, "2352be3db3177f0a07efbe6da5857615b8c9901d", "0000000000000000000000002352be3db3177f0a07efbe6da5857615b8c9901d"},
+		{ChainID(0xfe), "0000000000000000000000002352be3db3177f0a07efbe6da5857615b8c9901d", "0000000000000000000000002352be3db3177f0a07efbe6da5857615b8c9901d"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.chain.String(), func(t *testing.T) {
+			a, err := ParseAddress(test.chain, test.native)
+			require.NoError(t, err)
+			assert.Equal(t, test.hex, hex.EncodeToString(a[:]))
+
+			s, err := FormatAddress(test.chain, a)
+			require.NoError(t, err)
+			assert.Equal(t, test.native, s)
+			assert.Equal(t, test.native, a.NativeString(test.chain))
+		})
+	}
+}
+
+func TestParseAddressAlternativeForms(t *testing.T) {
+	qtum := mustHexAddress(t, "0000000000000000000000002352be3db3177f0a07efbe6da5857615b8c9901d")
+
+	tests := []struct {
+		name   string
+		chain  ChainID
+		native string
+		want   Address
+	}{
+		{"ethereum lowercase", ChainIDEthereum, "0x90f8bf6a479f320ead074411a4b0e7944ea8c9c1",
+			mustHexAddress(t, "00000000000000000000000090f8bf6a479f320ead074411a4b0e7944ea8c9c1")},
+		{"qtum prefixed hex", ChainIDQtum, "0x2352be3db3177f0a07efbe6da5857615b8c9901d", qtum},
+		{"qtum base58 testnet", ChainIDQtum, base58CheckEncode(qtum[12:], qtumPubKeyHashTestnet), qtum},
+		{"qtum base58 mainnet", ChainIDQtum, base58CheckEncode(qtum[12:], qtumPubKeyHashMainnet), qtum},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, err := ParseAddress(test.chain, test.native)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, a)
+		})
+	}
+}
+
+func TestParseAddressErrors(t *testing.T) {
+	qtum, err := hex.DecodeString("2352be3db3177f0a07efbe6da5857615b8c9901d")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		chain  ChainID
+		native string
+	}{
+		{"solana short", ChainIDSolana, "6sbzC1eH4FTujJXWj51eQe25cYvr4xfX"},
+		{"solana invalid base58", ChainIDSolana, "0OIl"},
+		{"ethereum bad checksum", ChainIDEthereum, "0x90f8bf6A479f320ead074411a4B0e7944Ea8c9C1"},
+		{"ethereum without prefix", ChainIDEthereum, "90F8bf6A479f320ead074411a4B0e7944Ea8c9C1"},
+		{"ethereum padded", ChainIDEthereum, "0x00000000000000000000000090f8bf6a479f320ead074411a4b0e7944ea8c9c1"},
+		{"terra wrong prefix", ChainIDTerra, "cosmos1x46rqay4d3cssq8gxxvqz8xt6nwlz4tdg4wfj5"},
+		{"terra bad checksum", ChainIDTerra, "terra1x46rqay4d3cssq8gxxvqz8xt6nwlz4td20k38w"},
+		{"qtum unknown version", ChainIDQtum, base58CheckEncode(qtum, 0)},
+		{"qtum bad checksum", ChainIDQtum, base58.Encode(append([]byte{qtumPubKeyHashMainnet}, qtum...))},
+		{"qtum short hex", ChainIDQtum, "2352be3db3177f0a07efbe6da5857615b8c9"},
+		{"unknown chain short hex", ChainID(0xfe), "2352be3db3177f0a07efbe6da5857615b8c9901d"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseAddress(test.chain, test.native)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFormatAddressPadding(t *testing.T) {
+	a := Address{1, 2, 3}
+	for _, c := range []ChainID{ChainIDEthereum, ChainIDTerra, ChainIDQtum} {
+		_, err := FormatAddress(c, a)
+		assert.True(t, errors.Is(err, ErrAddressPadding), "chain %s: %v", c, err)
+		assert.Equal(t, a.String(), a.NativeString(c))
+	}
+
+	s, err := FormatAddress(ChainIDSolana, a)
+	require.NoError(t, err)
+	assert.Equal(t, a.NativeString(ChainIDSolana), s)
+}
+
+func TestParseHexAddress(t *testing.T) {
+	a, err := ParseHexAddress("0x0000000000000000000000002352be3db3177f0a07efbe6da5857615b8c9901d")
+	require.NoError(t, err)
+	assert.Equal(t, mustHexAddress(t, "0000000000000000000000002352be3db3177f0a07efbe6da5857615b8c9901d"), a)
+
+	_, err = ParseHexAddress("2352be3db3177f0a07efbe6da5857615b8c9901d")
+	assert.Error(t, err)
+}
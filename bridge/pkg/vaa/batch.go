@@ -0,0 +1,177 @@
+package vaa
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Batches commit to their transfers using a binary Merkle tree. Leaves and inner nodes are hashed with distinct
+// prefixes, such that an inner node can't be passed off as a transfer. A node without sibling on its level is
+// promoted to the next level unchanged, which is unambiguous since the batch commits to the number of leaves.
+const (
+	batchLeafPrefix = 0x00
+	batchNodePrefix = 0x01
+)
+
+// BatchLeaf returns the Merkle tree leaf of a transfer: keccak256(0x00 || action || payload).
+func BatchLeaf(p Payload) (common.Hash, error) {
+	b, err := p.Serialize()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte{batchLeafPrefix, byte(p.ActionID())}, b), nil
+}
+
+func batchNode(left, right common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte{batchNodePrefix}, left[:], right[:])
+}
+
+// batchTransferSourceChain returns the source chain of a payload which can be part of a batch.
+func batchTransferSourceChain(p Payload) (ChainID, error) {
+	switch t := p.(type) {
+	case *BodyTransfer:
+		return t.SourceChain, nil
+	case *BodyTransferWithPayload:
+		return t.SourceChain, nil
+	default:
+		return 0, fmt.Errorf("payload with action %d can't be batched", p.ActionID())
+	}
+}
+
+// batchTree returns the levels of the Merkle tree of the given transfers, from the leaves to the root.
+func batchTree(sourceChain ChainID, transfers []Payload) ([][]common.Hash, error) {
+	if len(transfers) == 0 || len(transfers) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: %d", ErrBatchSize, len(transfers))
+	}
+
+	leaves := make([]common.Hash, len(transfers))
+	for i, t := range transfers {
+		c, err := batchTransferSourceChain(t)
+		if err != nil {
+			return nil, fmt.Errorf("transfer %d: %w", i, err)
+		}
+		if c != sourceChain {
+			return nil, fmt.Errorf("transfer %d: source chain %s doesn't match batch source chain %s", i, c, sourceChain)
+		}
+		if leaves[i], err = BatchLeaf(t); err != nil {
+			return nil, fmt.Errorf("transfer %d: %w", i, err)
+		}
+	}
+
+	levels := [][]common.Hash{leaves}
+	for level := leaves; len(level) > 1; {
+		next := make([]common.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+			} else {
+				next = append(next, batchNode(level[i], level[i+1]))
+			}
+		}
+		levels = append(levels, next)
+		level = next
+	}
+
+	return levels, nil
+}
+
+// NewBodyBatch returns a batch committing to the given transfers, in order. All transfers must originate from
+// sourceChain.
+func NewBodyBatch(sourceChain ChainID, blockHash common.Hash, transfers []Payload) (*BodyBatch, error) {
+	levels, err := batchTree(sourceChain, transfers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BodyBatch{
+		SourceChain: sourceChain,
+		BlockHash:   blockHash,
+		Count:       uint16(len(transfers)),
+		Root:        levels[len(levels)-1][0],
+	}, nil
+}
+
+// BatchProof returns the proof of inclusion of transfers[index] in the batch of the given transfers, which is
+// the list of sibling nodes from the leaf up to the root.
+func BatchProof(sourceChain ChainID, transfers []Payload, index int) ([]common.Hash, error) {
+	levels, err := batchTree(sourceChain, transfers)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(transfers) {
+		return nil, fmt.Errorf("index %d out of range for batch of %d transfers", index, len(transfers))
+	}
+
+	var proof []common.Hash
+	for _, level := range levels[:len(levels)-1] {
+		if sibling := index ^ 1; sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+// ErrNotIncluded means a transfer is not part of a batch at the given index.
+var ErrNotIncluded = errors.New("transfer not included in batch")
+
+// VerifyInclusion checks that the transfer is part of the batch at the given index, using a proof created by
+// BatchProof.
+func (v *BodyBatch) VerifyInclusion(transfer Payload, index int, proof []common.Hash) error {
+	if index < 0 || index >= int(v.Count) {
+		return fmt.Errorf("%w: index %d out of range for batch of %d transfers", ErrNotIncluded, index, v.Count)
+	}
+	c, err := batchTransferSourceChain(transfer)
+	if err != nil {
+		return err
+	}
+	if c != v.SourceChain {
+		return fmt.Errorf("%w: source chain %s doesn't match batch source chain %s", ErrNotIncluded, c, v.SourceChain)
+	}
+
+	h, err := BatchLeaf(transfer)
+	if err != nil {
+		return err
+	}
+
+	for width := int(v.Count); width > 1; width = (width + 1) / 2 {
+		switch {
+		case index%2 == 1:
+			if len(proof) == 0 {
+				return fmt.Errorf("%w: proof too short", ErrNotIncluded)
+			}
+			h, proof = batchNode(proof[0], h), proof[1:]
+		case index+1 < width:
+			if len(proof) == 0 {
+				return fmt.Errorf("%w: proof too short", ErrNotIncluded)
+			}
+			h, proof = batchNode(h, proof[0]), proof[1:]
+		}
+		index /= 2
+	}
+
+	if len(proof) != 0 {
+		return fmt.Errorf("%w: proof too long", ErrNotIncluded)
+	}
+	if h != v.Root {
+		return fmt.Errorf("%w: root mismatch", ErrNotIncluded)
+	}
+	return nil
+}
+
+// VerifyBatchedTransfer checks that a batch VAA carries valid signatures by the given guardian set (like
+// VerifySignatures, callers still need to check for quorum) and includes the transfer at the given index.
+func VerifyBatchedTransfer(v *VAA, addresses []common.Address, transfer Payload, index int, proof []common.Hash) error {
+	b, ok := v.Payload.(*BodyBatch)
+	if !ok {
+		return fmt.Errorf("not a batch VAA: action %d", v.Payload.ActionID())
+	}
+	if !v.VerifySignatures(addresses) {
+		return errors.New("invalid signatures")
+	}
+	return b.VerifyInclusion(transfer, index, proof)
+}
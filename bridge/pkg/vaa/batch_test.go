@@ -0,0 +1,104 @@
+package vaa
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBatchTransfers(n int) []Payload {
+	transfers := make([]Payload, n)
+	for i := range transfers {
+		t := BodyTransfer{
+			Nonce:         uint32(i),
+			SourceChain:   ChainIDEthereum,
+			TargetChain:   ChainIDSolana,
+			SourceAddress: Address{2, 1, 4},
+			TargetAddress: Address{2, 1, byte(i)},
+			Asset:         &AssetMeta{Chain: ChainIDEthereum, Address: Address{9, 2, 4}, Decimals: 8},
+			Amount:        big.NewInt(int64(29 + i)),
+		}
+		if i%3 == 2 {
+			transfers[i] = &BodyTransferWithPayload{BodyTransfer: t, Fee: big.NewInt(1), Payload: []byte("swap")}
+		} else {
+			transfers[i] = &t
+		}
+	}
+	return transfers
+}
+
+func TestBatchInclusion(t *testing.T) {
+	blockHash := common.HexToHash("0x06f541f5ecfc43407c31587aa6ac3a689e8960f36dc23c332db5510dfc6a4063")
+
+	for n := 1; n <= 9; n++ {
+		transfers := testBatchTransfers(n)
+		b, err := NewBodyBatch(ChainIDEthereum, blockHash, transfers)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(n), b.Count)
+
+		for i, transfer := range transfers {
+			proof, err := BatchProof(ChainIDEthereum, transfers, i)
+			require.NoError(t, err)
+			assert.NoError(t, b.VerifyInclusion(transfer, i, proof), "batch of %d, transfer %d", n, i)
+
+			// The proof is only valid at the transfer's index.
+			for j := range transfers {
+				if j != i {
+					assert.Error(t, b.VerifyInclusion(transfer, j, proof), "batch of %d, transfer %d at %d", n, i, j)
+				}
+			}
+			if n > 1 {
+				assert.True(t, errors.Is(b.VerifyInclusion(transfer, i, proof[1:]), ErrNotIncluded))
+			}
+			assert.True(t, errors.Is(b.VerifyInclusion(transfer, i, append(proof, common.Hash{})), ErrNotIncluded))
+		}
+	}
+}
+
+func TestBatchInclusionRejectsForgeries(t *testing.T) {
+	transfers := testBatchTransfers(5)
+	b, err := NewBodyBatch(ChainIDEthereum, common.Hash{1}, transfers)
+	require.NoError(t, err)
+	proof, err := BatchProof(ChainIDEthereum, transfers, 0)
+	require.NoError(t, err)
+
+	forged := *transfers[0].(*BodyTransfer)
+	forged.Amount = big.NewInt(1000)
+	assert.True(t, errors.Is(b.VerifyInclusion(&forged, 0, proof), ErrNotIncluded))
+
+	// Only transfers can be part of a batch, so inner nodes can't be passed off as leaves.
+	levels, err := batchTree(ChainIDEthereum, transfers)
+	require.NoError(t, err)
+	assert.Error(t, b.VerifyInclusion(&BodyMessage{Payload: levels[1][0][:]}, 0, proof[1:]))
+
+	_, err = NewBodyBatch(ChainIDSolana, common.Hash{1}, transfers)
+	assert.Error(t, err, "batch with mismatching source chain")
+	_, err = NewBodyBatch(ChainIDEthereum, common.Hash{1}, nil)
+	assert.True(t, errors.Is(err, ErrBatchSize))
+	_, err = NewBodyBatch(ChainIDEthereum, common.Hash{1}, testBatchTransfers(MaxBatchSize+1))
+	assert.True(t, errors.Is(err, ErrBatchSize))
+}
+
+func TestVerifyBatchedTransfer(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addresses := []common.Address{crypto.PubkeyToAddress(key.PublicKey)}
+
+	transfers := testBatchTransfers(3)
+	b, err := NewBodyBatch(ChainIDEthereum, common.Hash{1}, transfers)
+	require.NoError(t, err)
+	v := &VAA{Version: SupportedVAAVersion, Timestamp: time.Unix(2837, 0), Payload: b}
+	v.AddSignature(key, 0)
+
+	proof, err := BatchProof(ChainIDEthereum, transfers, 2)
+	require.NoError(t, err)
+	assert.NoError(t, VerifyBatchedTransfer(v, addresses, transfers[2], 2, proof))
+	assert.Error(t, VerifyBatchedTransfer(v, []common.Address{{1}}, transfers[2], 2, proof))
+	assert.Error(t, VerifyBatchedTransfer(&VAA{Payload: transfers[2]}, addresses, transfers[2], 2, proof))
+}
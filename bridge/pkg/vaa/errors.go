@@ -0,0 +1,52 @@
+package vaa
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by Unmarshal and the payload parsers, wrapped in a ParseError. Use errors.Is to check for them.
+var (
+	// ErrTruncated means the data ended before the field was complete.
+	ErrTruncated = errors.New("unexpected end of data")
+	// ErrTrailingData means there's data after the end of the payload.
+	ErrTrailingData = errors.New("trailing data")
+	// ErrUnsupportedVersion means the VAA has a version other than SupportedVAAVersion.
+	ErrUnsupportedVersion = errors.New("unsupported version")
+	// ErrUnknownAction means no payload type is registered for the VAA's action.
+	ErrUnknownAction = errors.New("unknown action")
+	// ErrTooManySignatures means the VAA has more signatures than the largest possible guardian set has members.
+	ErrTooManySignatures = errors.New("too many signatures")
+	// ErrSignatureOrder means the signatures are not sorted by strictly ascending guardian index.
+	ErrSignatureOrder = errors.New("signature indices not strictly ascending")
+	// ErrTooManyKeys means a guardian set update has more keys than the largest possible guardian set.
+	ErrTooManyKeys = errors.New("too many keys")
+	// ErrZeroAddress means a guardian set update includes the zero address as key.
+	ErrZeroAddress = errors.New("zero address")
+	// ErrDuplicateKey means a guardian set update includes the same key more than once.
+	ErrDuplicateKey = errors.New("duplicate key")
+	// ErrPayloadTooLong means a message payload is longer than MaxMessagePayloadLength.
+	ErrPayloadTooLong = errors.New("payload too long")
+	// ErrFeeExceedsAmount means a transfer's relayer fee is larger than the amount transferred.
+	ErrFeeExceedsAmount = errors.New("fee exceeds amount")
+	// ErrBatchSize means a batch is empty or has more than MaxBatchSize transfers.
+	ErrBatchSize = errors.New("invalid batch size")
+	// ErrInvalidBool means a boolean flag is encoded as something other than 0 or 1.
+	ErrInvalidBool = errors.New("invalid boolean")
+)
+
+// ParseError is returned when a VAA or payload does not have a valid, canonical binary encoding.
+type ParseError struct {
+	// Field is the name of the field that failed to parse, like "signatures[2].index".
+	Field string
+	// Err is the reason, usually one of the Err* errors of this package.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid %s: %v", e.Field, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
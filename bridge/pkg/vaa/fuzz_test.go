@@ -0,0 +1,139 @@
+//go:build go1.18
+// +build go1.18
+
+package vaa
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fuzzSeeds returns the binary encodings of VAAs with each of the built-in payload types.
+func fuzzSeeds(f *testing.F) [][]byte {
+	var seeds [][]byte
+	for _, p := range []Payload{
+		&BodyTransfer{
+			Nonce:         38,
+			SourceChain:   ChainIDEthereum,
+			TargetChain:   ChainIDSolana,
+			SourceAddress: Address{2, 1, 4},
+			TargetAddress: Address{2, 1, 3},
+			Asset:         &AssetMeta{Chain: ChainIDEthereum, Address: Address{9, 2, 4}, Decimals: 8},
+			Amount:        big.NewInt(29),
+		},
+		&BodyTransferWithPayload{
+			BodyTransfer: BodyTransfer{
+				Nonce:         38,
+				SourceChain:   ChainIDEthereum,
+				TargetChain:   ChainIDSolana,
+				TargetAddress: Address{2, 1, 3},
+				Asset:         &AssetMeta{Chain: ChainIDEthereum, Address: Address{9, 2, 4}, Decimals: 8},
+				Amount:        big.NewInt(29),
+			},
+			Fee:     big.NewInt(2),
+			Payload: []byte("swap"),
+		},
+		&BodyGuardianSetUpdate{Keys: []common.Address{{1}, {2}}, NewIndex: 2},
+		&BodyBatch{SourceChain: ChainIDEthereum, BlockHash: common.Hash{4, 2}, Count: 3, Root: common.Hash{1, 2, 3}},
+		&BodyBridgePause{ChainID: ChainIDTerra, Paused: true},
+		&BodyContractUpgrade{ChainID: ChainIDSolana, NewContract: Address{1, 3, 4, 5, 2, 3}},
+		&BodyMessage{EmitterChain: ChainIDSolana, EmitterAddress: Address{4, 2}, Sequence: 3, Payload: []byte("hello")},
+	} {
+		v := &VAA{
+			Version:          SupportedVAAVersion,
+			GuardianSetIndex: 9,
+			Signatures:       []*Signature{{Index: 0}, {Index: 3}},
+			Timestamp:        time.Unix(2837, 0),
+			Payload:          p,
+		}
+		b, err := v.Marshal()
+		if err != nil {
+			f.Fatal(err)
+		}
+		seeds = append(seeds, b)
+	}
+	return seeds
+}
+
+// FuzzUnmarshal checks that Unmarshal only accepts canonical encodings: any VAA it returns serializes to
+// exactly the data it was parsed from.
+func FuzzUnmarshal(f *testing.F) {
+	for _, b := range fuzzSeeds(f) {
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := Unmarshal(data)
+		if err != nil {
+			return
+		}
+
+		b, err := v.Marshal()
+		if err != nil {
+			t.Fatalf("failed to marshal parsed VAA: %v", err)
+		}
+		if !bytes.Equal(data, b) {
+			t.Fatalf("non-canonical encoding accepted:\n in: %x\nout: %x", data, b)
+		}
+	})
+}
+
+// fuzzParser checks that a payload parser only accepts canonical encodings, like FuzzUnmarshal.
+func fuzzParser(f *testing.F, action Action, parse func(r io.Reader) (Payload, error)) {
+	for _, b := range fuzzSeeds(f) {
+		// Payloads follow the action, which is at a fixed offset for VAAs with two signatures.
+		offset := 1 + 4 + 1 + 2*66 + 4
+		if Action(b[offset]) == action {
+			f.Add(b[offset+1:])
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bytes.NewReader(data)
+		p, err := parse(r)
+		if err != nil || r.Len() != 0 {
+			return
+		}
+
+		b, err := p.Serialize()
+		if err != nil {
+			t.Fatalf("failed to serialize parsed payload: %v", err)
+		}
+		if !bytes.Equal(data, b) {
+			t.Fatalf("non-canonical encoding accepted:\n in: %x\nout: %x", data, b)
+		}
+	})
+}
+
+func FuzzParseBodyTransfer(f *testing.F) {
+	fuzzParser(f, ActionTransfer, parseBodyTransfer)
+}
+
+func FuzzParseBodyTransferWithPayload(f *testing.F) {
+	fuzzParser(f, ActionTransferWithPayload, parseBodyTransferWithPayload)
+}
+
+func FuzzParseBodyGuardianSetUpdate(f *testing.F) {
+	fuzzParser(f, ActionGuardianSetUpdate, parseBodyGuardianSetUpdate)
+}
+
+func FuzzParseBodyMessage(f *testing.F) {
+	fuzzParser(f, ActionMessage, parseBodyMessage)
+}
+
+func FuzzParseBodyBatch(f *testing.F) {
+	fuzzParser(f, ActionBatch, parseBodyBatch)
+}
+
+func FuzzParseBodyBridgePause(f *testing.F) {
+	fuzzParser(f, ActionBridgePause, parseBodyBridgePause)
+}
+
+func FuzzParseBodyContractUpgrade(f *testing.F) {
+	fuzzParser(f, ActionContractUpgrade, parseBodyContractUpgrade)
+}
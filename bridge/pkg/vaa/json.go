@@ -0,0 +1,470 @@
+package vaa
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// JSON encoding of VAAs, for human consumption and tooling. Byte strings are 0x-prefixed hex, amounts are decimal
+// strings, timestamps are RFC3339 and payloads carry a "type" discriminator. The encoding is lossless - a VAA
+// decoded from JSON has the same binary representation as the VAA it was encoded from.
+
+// Payload type discriminators of the built-in payload types. Other payload types register their own, see PayloadType.
+const (
+	payloadTypeTransfer            = "transfer"
+	payloadTypeTransferWithPayload = "transfer_with_payload"
+	payloadTypeGuardianSetUpdate   = "guardian_set_update"
+	payloadTypeContractUpgrade     = "contract_upgrade"
+	payloadTypeMessage             = "message"
+	payloadTypeBatch               = "batch"
+	payloadTypeBridgePause         = "bridge_pause"
+)
+
+type (
+	vaaJSON struct {
+		Version          uint8           `json:"version"`
+		GuardianSetIndex uint32          `json:"guardian_set_index"`
+		Signatures       []*Signature    `json:"signatures"`
+		Timestamp        time.Time       `json:"timestamp"`
+		Payload          json.RawMessage `json:"payload"`
+	}
+
+	signatureJSON struct {
+		Index     uint8         `json:"index"`
+		Signature hexutil.Bytes `json:"signature"`
+	}
+
+	assetMetaJSON struct {
+		Chain    ChainID `json:"chain"`
+		Address  Address `json:"address"`
+		Decimals uint8   `json:"decimals"`
+	}
+
+	// payloadTypeJSON is embedded into the JSON encoding of every payload body.
+	payloadTypeJSON struct {
+		Type string `json:"type"`
+	}
+
+	bodyTransferJSON struct {
+		payloadTypeJSON
+		Nonce         uint32     `json:"nonce"`
+		SourceChain   ChainID    `json:"source_chain"`
+		TargetChain   ChainID    `json:"target_chain"`
+		SourceAddress Address    `json:"source_address"`
+		TargetAddress Address    `json:"target_address"`
+		Asset         *AssetMeta `json:"asset"`
+		Amount        string     `json:"amount"`
+	}
+
+	bodyTransferWithPayloadJSON struct {
+		bodyTransferJSON
+		Fee     string        `json:"fee"`
+		Payload hexutil.Bytes `json:"payload"`
+	}
+
+	bodyGuardianSetUpdateJSON struct {
+		payloadTypeJSON
+		Keys     []common.Address `json:"keys"`
+		NewIndex uint32           `json:"new_index"`
+	}
+
+	bodyMessageJSON struct {
+		payloadTypeJSON
+		EmitterChain     ChainID       `json:"emitter_chain"`
+		EmitterAddress   Address       `json:"emitter_address"`
+		Sequence         uint64        `json:"sequence"`
+		ConsistencyLevel uint8         `json:"consistency_level"`
+		Payload          hexutil.Bytes `json:"payload"`
+	}
+
+	bodyBatchJSON struct {
+		payloadTypeJSON
+		SourceChain ChainID     `json:"source_chain"`
+		BlockHash   common.Hash `json:"block_hash"`
+		Count       uint16      `json:"count"`
+		Root        common.Hash `json:"root"`
+	}
+
+	bodyBridgePauseJSON struct {
+		payloadTypeJSON
+		ChainID ChainID `json:"chain_id"`
+		Paused  bool    `json:"paused"`
+	}
+
+	bodyContractUpgradeJSON struct {
+		payloadTypeJSON
+		ChainID     uint8   `json:"chain_id"`
+		NewContract Address `json:"new_contract"`
+	}
+)
+
+func (v *VAA) MarshalJSON() ([]byte, error) {
+	var payload json.RawMessage
+	if v.Payload != nil {
+		var err error
+		payload, err = json.Marshal(v.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		payload, err = withPayloadType(payload, v.Payload.ActionID())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(&vaaJSON{
+		Version:          v.Version,
+		GuardianSetIndex: v.GuardianSetIndex,
+		Signatures:       v.Signatures,
+		Timestamp:        v.Timestamp.UTC(),
+		Payload:          payload,
+	})
+}
+
+func (v *VAA) UnmarshalJSON(data []byte) error {
+	var j vaaJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	var t payloadTypeJSON
+	if err := json.Unmarshal(j.Payload, &t); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	pt, ok := payloadTypeByName(t.Type)
+	if !ok {
+		return fmt.Errorf("unknown payload type: %q", t.Type)
+	}
+	payload := pt.New()
+	if err := json.Unmarshal(j.Payload, payload); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", t.Type, err)
+	}
+
+	*v = VAA{
+		Version:          j.Version,
+		GuardianSetIndex: j.GuardianSetIndex,
+		Signatures:       j.Signatures,
+		// Same representation as in VAAs decoded from their binary encoding.
+		Timestamp: time.Unix(j.Timestamp.Unix(), 0),
+		Payload:   payload,
+	}
+	return nil
+}
+
+// withPayloadType adds the type discriminator of the given action to a JSON-encoded payload, unless the payload's
+// own encoding already includes it.
+func withPayloadType(payload json.RawMessage, action Action) (json.RawMessage, error) {
+	pt, ok := payloadTypes[action]
+	if !ok {
+		return nil, fmt.Errorf("unknown action: %d", action)
+	}
+
+	var t payloadTypeJSON
+	if err := json.Unmarshal(payload, &t); err != nil {
+		return nil, fmt.Errorf("payload is not a JSON object: %w", err)
+	}
+	switch t.Type {
+	case pt.Name:
+		return payload, nil
+	case "":
+	default:
+		return nil, fmt.Errorf("payload type %q does not match %q", t.Type, pt.Name)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("payload is not a JSON object: %w", err)
+	}
+	fields["type"], _ = json.Marshal(pt.Name)
+	return json.Marshal(fields)
+}
+
+func (s *Signature) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&signatureJSON{Index: s.Index, Signature: s.Signature[:]})
+}
+
+func (s *Signature) UnmarshalJSON(data []byte) error {
+	var j signatureJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if len(j.Signature) != len(s.Signature) {
+		return fmt.Errorf("invalid signature length: %d", len(j.Signature))
+	}
+
+	s.Index = j.Index
+	copy(s.Signature[:], j.Signature)
+	return nil
+}
+
+func (a Address) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexutil.Encode(a[:]))
+}
+
+func (a *Address) UnmarshalJSON(data []byte) error {
+	var b hexutil.Bytes
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	if len(b) != len(a) {
+		return fmt.Errorf("invalid address length: %d", len(b))
+	}
+
+	copy(a[:], b)
+	return nil
+}
+
+func (a *AssetMeta) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&assetMetaJSON{Chain: a.Chain, Address: a.Address, Decimals: a.Decimals})
+}
+
+func (a *AssetMeta) UnmarshalJSON(data []byte) error {
+	var j assetMetaJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*a = AssetMeta{Chain: j.Chain, Address: j.Address, Decimals: j.Decimals}
+	return nil
+}
+
+// checkPayloadType verifies that the type discriminator of a payload matches the body it's decoded into.
+// The discriminator may be omitted when decoding a body on its own.
+func checkPayloadType(t payloadTypeJSON, want string) error {
+	if t.Type != "" && t.Type != want {
+		return fmt.Errorf("payload type %q does not match %q", t.Type, want)
+	}
+	return nil
+}
+
+func (v *BodyTransfer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.toJSON())
+}
+
+func (v *BodyTransfer) toJSON() bodyTransferJSON {
+	j := bodyTransferJSON{
+		payloadTypeJSON: payloadTypeJSON{payloadTypeTransfer},
+		Nonce:           v.Nonce,
+		SourceChain:     v.SourceChain,
+		TargetChain:     v.TargetChain,
+		SourceAddress:   v.SourceAddress,
+		TargetAddress:   v.TargetAddress,
+		Asset:           v.Asset,
+	}
+	if v.Amount != nil {
+		j.Amount = v.Amount.String()
+	}
+	return j
+}
+
+func (v *BodyTransfer) UnmarshalJSON(data []byte) error {
+	var j bodyTransferJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := checkPayloadType(j.payloadTypeJSON, payloadTypeTransfer); err != nil {
+		return err
+	}
+	return v.fromJSON(&j)
+}
+
+func (v *BodyTransfer) fromJSON(j *bodyTransferJSON) error {
+	amount, err := parseAmount(j.Amount)
+	if err != nil {
+		return fmt.Errorf("invalid amount: %w", err)
+	}
+
+	*v = BodyTransfer{
+		Nonce:         j.Nonce,
+		SourceChain:   j.SourceChain,
+		TargetChain:   j.TargetChain,
+		SourceAddress: j.SourceAddress,
+		TargetAddress: j.TargetAddress,
+		Asset:         j.Asset,
+		Amount:        amount,
+	}
+	return nil
+}
+
+// parseAmount parses a decimal amount which fits the 32 bytes it's encoded as.
+func parseAmount(s string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok || amount.Sign() < 0 || amount.BitLen() > 256 {
+		return nil, fmt.Errorf("not a 256 bit unsigned integer: %q", s)
+	}
+	return amount, nil
+}
+
+func (v *BodyTransferWithPayload) MarshalJSON() ([]byte, error) {
+	j := &bodyTransferWithPayloadJSON{
+		bodyTransferJSON: v.BodyTransfer.toJSON(),
+		Fee:              "0",
+		Payload:          v.Payload,
+	}
+	j.Type = payloadTypeTransferWithPayload
+	if v.Fee != nil {
+		j.Fee = v.Fee.String()
+	}
+	return json.Marshal(j)
+}
+
+func (v *BodyTransferWithPayload) UnmarshalJSON(data []byte) error {
+	var j bodyTransferWithPayloadJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := checkPayloadType(j.payloadTypeJSON, payloadTypeTransferWithPayload); err != nil {
+		return err
+	}
+
+	var t BodyTransfer
+	if err := t.fromJSON(&j.bodyTransferJSON); err != nil {
+		return err
+	}
+	fee, err := parseAmount(j.Fee)
+	if err != nil {
+		return fmt.Errorf("invalid fee: %w", err)
+	}
+	if fee.Cmp(t.Amount) > 0 {
+		return ErrFeeExceedsAmount
+	}
+	if len(j.Payload) > MaxTransferPayloadLength {
+		return fmt.Errorf("payload too long: %d bytes, maximum is %d", len(j.Payload), MaxTransferPayloadLength)
+	}
+
+	*v = BodyTransferWithPayload{BodyTransfer: t, Fee: fee, Payload: j.Payload}
+	return nil
+}
+
+func (v *BodyGuardianSetUpdate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&bodyGuardianSetUpdateJSON{
+		payloadTypeJSON: payloadTypeJSON{payloadTypeGuardianSetUpdate},
+		Keys:            v.Keys,
+		NewIndex:        v.NewIndex,
+	})
+}
+
+func (v *BodyGuardianSetUpdate) UnmarshalJSON(data []byte) error {
+	var j bodyGuardianSetUpdateJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := checkPayloadType(j.payloadTypeJSON, payloadTypeGuardianSetUpdate); err != nil {
+		return err
+	}
+
+	*v = BodyGuardianSetUpdate{Keys: j.Keys, NewIndex: j.NewIndex}
+	return nil
+}
+
+func (v *BodyBatch) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&bodyBatchJSON{
+		payloadTypeJSON: payloadTypeJSON{payloadTypeBatch},
+		SourceChain:     v.SourceChain,
+		BlockHash:       v.BlockHash,
+		Count:           v.Count,
+		Root:            v.Root,
+	})
+}
+
+func (v *BodyBatch) UnmarshalJSON(data []byte) error {
+	var j bodyBatchJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := checkPayloadType(j.payloadTypeJSON, payloadTypeBatch); err != nil {
+		return err
+	}
+	if j.Count == 0 || j.Count > MaxBatchSize {
+		return fmt.Errorf("%w: %d", ErrBatchSize, j.Count)
+	}
+
+	*v = BodyBatch{
+		SourceChain: j.SourceChain,
+		BlockHash:   j.BlockHash,
+		Count:       j.Count,
+		Root:        j.Root,
+	}
+	return nil
+}
+
+func (v *BodyBridgePause) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&bodyBridgePauseJSON{
+		payloadTypeJSON: payloadTypeJSON{payloadTypeBridgePause},
+		ChainID:         v.ChainID,
+		Paused:          v.Paused,
+	})
+}
+
+func (v *BodyBridgePause) UnmarshalJSON(data []byte) error {
+	var j bodyBridgePauseJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := checkPayloadType(j.payloadTypeJSON, payloadTypeBridgePause); err != nil {
+		return err
+	}
+
+	*v = BodyBridgePause{ChainID: j.ChainID, Paused: j.Paused}
+	return nil
+}
+
+func (v *BodyContractUpgrade) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&bodyContractUpgradeJSON{
+		payloadTypeJSON: payloadTypeJSON{payloadTypeContractUpgrade},
+		ChainID:         v.ChainID,
+		NewContract:     v.NewContract,
+	})
+}
+
+func (v *BodyContractUpgrade) UnmarshalJSON(data []byte) error {
+	var j bodyContractUpgradeJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := checkPayloadType(j.payloadTypeJSON, payloadTypeContractUpgrade); err != nil {
+		return err
+	}
+
+	*v = BodyContractUpgrade{ChainID: j.ChainID, NewContract: j.NewContract}
+	return nil
+}
+
+func (v *BodyMessage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&bodyMessageJSON{
+		payloadTypeJSON:  payloadTypeJSON{payloadTypeMessage},
+		EmitterChain:     v.EmitterChain,
+		EmitterAddress:   v.EmitterAddress,
+		Sequence:         v.Sequence,
+		ConsistencyLevel: v.ConsistencyLevel,
+		Payload:          v.Payload,
+	})
+}
+
+func (v *BodyMessage) UnmarshalJSON(data []byte) error {
+	var j bodyMessageJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := checkPayloadType(j.payloadTypeJSON, payloadTypeMessage); err != nil {
+		return err
+	}
+	if len(j.Payload) > MaxMessagePayloadLength {
+		return fmt.Errorf("payload too long: %d bytes, maximum is %d", len(j.Payload), MaxMessagePayloadLength)
+	}
+
+	*v = BodyMessage{
+		EmitterChain:     j.EmitterChain,
+		EmitterAddress:   j.EmitterAddress,
+		Sequence:         j.Sequence,
+		ConsistencyLevel: j.ConsistencyLevel,
+		Payload:          j.Payload,
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package vaa
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	key, err := crypto.HexToECDSA("cfb12303a19cde580bb4dd771639b0d26bc68353645571a8cff516ab2ee113a0")
+	require.NoError(t, err)
+
+	amount, ok := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	require.True(t, ok)
+
+	tests := []struct {
+		name string
+		vaa  *VAA
+	}{
+		{
+			name: "BodyTransfer",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Timestamp:        time.Unix(1605105600, 0),
+				Payload: &BodyTransfer{
+					Nonce:         38,
+					SourceChain:   ChainIDEthereum,
+					TargetChain:   ChainIDSolana,
+					SourceAddress: Address{2, 1, 4},
+					TargetAddress: Address{2, 1, 3},
+					Asset: &AssetMeta{
+						Chain:    ChainIDEthereum,
+						Address:  Address{9, 2, 4},
+						Decimals: 8,
+					},
+					Amount: amount,
+				},
+			},
+		},
+		{
+			name: "BodyTransferWithPayload",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Timestamp:        time.Unix(1605105600, 0),
+				Payload: &BodyTransferWithPayload{
+					BodyTransfer: BodyTransfer{
+						Nonce:         38,
+						SourceChain:   ChainIDEthereum,
+						TargetChain:   ChainIDSolana,
+						SourceAddress: Address{2, 1, 4},
+						TargetAddress: Address{2, 1, 3},
+						Asset: &AssetMeta{
+							Chain:    ChainIDEthereum,
+							Address:  Address{9, 2, 4},
+							Decimals: 8,
+						},
+						Amount: amount,
+					},
+					Fee:     big.NewInt(1000),
+					Payload: []byte("swap"),
+				},
+			},
+		},
+		{
+			name: "GuardianSetUpdate",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Timestamp:        time.Unix(2837, 0),
+				Payload: &BodyGuardianSetUpdate{
+					Keys:     []common.Address{{1}, crypto.PubkeyToAddress(key.PublicKey)},
+					NewIndex: 10,
+				},
+			},
+		},
+		{
+			name: "Message",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Timestamp:        time.Unix(2837, 0),
+				Payload: &BodyMessage{
+					EmitterChain:     ChainIDSolana,
+					EmitterAddress:   Address{4, 2},
+					Sequence:         1 << 40,
+					ConsistencyLevel: 32,
+					Payload:          []byte("hello"),
+				},
+			},
+		},
+		{
+			name: "Batch",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Timestamp:        time.Unix(2837, 0),
+				Payload: &BodyBatch{
+					SourceChain: ChainIDEthereum,
+					BlockHash:   common.Hash{4, 2},
+					Count:       3,
+					Root:        common.Hash{1, 2, 3},
+				},
+			},
+		},
+		{
+			name: "BridgePause",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Timestamp:        time.Unix(2837, 0),
+				Payload: &BodyBridgePause{
+					ChainID: ChainIDTerra,
+					Paused:  true,
+				},
+			},
+		},
+		{
+			name: "ContractUpgrade",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Timestamp:        time.Unix(2837, 0),
+				Payload: &BodyContractUpgrade{
+					ChainID:     ChainIDSolana,
+					NewContract: Address{1, 3, 4, 5, 2, 3},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.vaa.AddSignature(key, 3)
+
+			b, err := json.Marshal(test.vaa)
+			require.NoError(t, err)
+
+			var parsed VAA
+			require.NoError(t, json.Unmarshal(b, &parsed))
+			assert.EqualValues(t, test.vaa, &parsed)
+
+			want, err := test.vaa.Marshal()
+			require.NoError(t, err)
+			got, err := parsed.Marshal()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestJSONEncoding(t *testing.T) {
+	v := &VAA{
+		Version:          1,
+		GuardianSetIndex: 2,
+		Signatures:       []*Signature{{Index: 1, Signature: [65]byte{0xab}}},
+		Timestamp:        time.Unix(1605105600, 0),
+		Payload: &BodyTransfer{
+			Nonce:         7,
+			SourceChain:   ChainIDEthereum,
+			TargetChain:   ChainIDSolana,
+			SourceAddress: Address{1},
+			TargetAddress: Address{2},
+			Asset:         &AssetMeta{Chain: ChainIDEthereum, Address: Address{3}, Decimals: 18},
+			Amount:        big.NewInt(1000),
+		},
+	}
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"version": 1,
+		"guardian_set_index": 2,
+		"signatures": [{"index": 1, "signature": "0xab`+strings.Repeat("00", 64)+`"}],
+		"timestamp": "2020-11-11T14:40:00Z",
+		"payload": {
+			"type": "transfer",
+			"nonce": 7,
+			"source_chain": 2,
+			"target_chain": 1,
+			"source_address": "0x01`+strings.Repeat("00", 31)+`",
+			"target_address": "0x02`+strings.Repeat("00", 31)+`",
+			"asset": {"chain": 2, "address": "0x03`+strings.Repeat("00", 31)+`", "decimals": 18},
+			"amount": "1000"
+		}
+	}`, string(b))
+}
+
+func TestJSONUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{name: "unknown payload type", json: `{"version": 1, "timestamp": "2020-11-11T14:40:00Z", "payload": {"type": "foo"}}`},
+		{name: "missing payload", json: `{"version": 1, "timestamp": "2020-11-11T14:40:00Z"}`},
+		{name: "short signature", json: `{"version": 1, "signatures": [{"index": 0, "signature": "0xab"}], "timestamp": "2020-11-11T14:40:00Z", "payload": {"type": "contract_upgrade"}}`},
+		{name: "short address", json: `{"version": 1, "timestamp": "2020-11-11T14:40:00Z", "payload": {"type": "contract_upgrade", "new_contract": "0x01"}}`},
+		{name: "invalid amount", json: `{"version": 1, "timestamp": "2020-11-11T14:40:00Z", "payload": {"type": "transfer", "amount": "1e3"}}`},
+		{name: "fee exceeds amount", json: `{"version": 1, "timestamp": "2020-11-11T14:40:00Z", "payload": {"type": "transfer_with_payload", "amount": "1", "fee": "2"}}`},
+		{name: "empty batch", json: `{"version": 1, "timestamp": "2020-11-11T14:40:00Z", "payload": {"type": "batch", "count": 0}}`},
+		{name: "negative amount", json: `{"version": 1, "timestamp": "2020-11-11T14:40:00Z", "payload": {"type": "transfer", "amount": "-1"}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var v VAA
+			assert.Error(t, json.Unmarshal([]byte(test.json), &v))
+		})
+	}
+
+	var b BodyTransfer
+	assert.Error(t, json.Unmarshal([]byte(`{"type": "contract_upgrade", "amount": "1"}`), &b))
+}
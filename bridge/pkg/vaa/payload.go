@@ -0,0 +1,97 @@
+package vaa
+
+import (
+	"fmt"
+	"io"
+)
+
+// PayloadType describes how to decode a payload identified by its action.
+type PayloadType struct {
+	// Action identifies the payload type in the binary encoding.
+	Action Action
+	// Name identifies the payload type in the JSON encoding, like "transfer".
+	Name string
+	// Parse parses the binary representation of a payload, excluding the action.
+	Parse func(r io.Reader) (Payload, error)
+	// New returns an empty payload to decode JSON into. Payloads are decoded using encoding/json, and may
+	// implement json.Unmarshaler to customize their encoding.
+	New func() Payload
+}
+
+// payloadTypes maps actions to the payload types registered using RegisterPayloadType.
+var payloadTypes = map[Action]PayloadType{}
+
+// payloadTypeNames maps JSON type discriminators to actions.
+var payloadTypeNames = map[string]Action{}
+
+func init() {
+	RegisterPayloadType(PayloadType{
+		Action: ActionTransfer,
+		Name:   payloadTypeTransfer,
+		Parse:  parseBodyTransfer,
+		New:    func() Payload { return &BodyTransfer{} },
+	})
+	RegisterPayloadType(PayloadType{
+		Action: ActionTransferWithPayload,
+		Name:   payloadTypeTransferWithPayload,
+		Parse:  parseBodyTransferWithPayload,
+		New:    func() Payload { return &BodyTransferWithPayload{} },
+	})
+	RegisterPayloadType(PayloadType{
+		Action: ActionGuardianSetUpdate,
+		Name:   payloadTypeGuardianSetUpdate,
+		Parse:  parseBodyGuardianSetUpdate,
+		New:    func() Payload { return &BodyGuardianSetUpdate{} },
+	})
+	RegisterPayloadType(PayloadType{
+		Action: ActionMessage,
+		Name:   payloadTypeMessage,
+		Parse:  parseBodyMessage,
+		New:    func() Payload { return &BodyMessage{} },
+	})
+	RegisterPayloadType(PayloadType{
+		Action: ActionBatch,
+		Name:   payloadTypeBatch,
+		Parse:  parseBodyBatch,
+		New:    func() Payload { return &BodyBatch{} },
+	})
+	RegisterPayloadType(PayloadType{
+		Action: ActionBridgePause,
+		Name:   payloadTypeBridgePause,
+		Parse:  parseBodyBridgePause,
+		New:    func() Payload { return &BodyBridgePause{} },
+	})
+	RegisterPayloadType(PayloadType{
+		Action: ActionContractUpgrade,
+		Name:   payloadTypeContractUpgrade,
+		Parse:  parseBodyContractUpgrade,
+		New:    func() Payload { return &BodyContractUpgrade{} },
+	})
+}
+
+// RegisterPayloadType makes Unmarshal and the JSON decoder decode payloads of the given type. This allows
+// packages outside of vaa to define their own actions. It is not thread safe and must only be called from
+// init functions.
+func RegisterPayloadType(t PayloadType) {
+	if t.Name == "" || t.Parse == nil || t.New == nil {
+		panic(fmt.Sprintf("incomplete payload type for action %d", t.Action))
+	}
+	if _, ok := payloadTypes[t.Action]; ok {
+		panic(fmt.Sprintf("action %d is already registered", t.Action))
+	}
+	if _, ok := payloadTypeNames[t.Name]; ok {
+		panic(fmt.Sprintf("payload type name %q is already registered", t.Name))
+	}
+
+	payloadTypes[t.Action] = t
+	payloadTypeNames[t.Name] = t.Action
+}
+
+// payloadTypeByName returns the payload type with the given JSON type discriminator.
+func payloadTypeByName(name string) (PayloadType, bool) {
+	a, ok := payloadTypeNames[name]
+	if !ok {
+		return PayloadType{}, false
+	}
+	return payloadTypes[a], true
+}
@@ -0,0 +1,87 @@
+package vaa_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certusone/wormhole/bridge/pkg/vaa"
+)
+
+const actionTestMessage vaa.Action = 0x7f
+
+// testMessage is a payload type defined outside of package vaa.
+type testMessage struct {
+	Data []byte `json:"data"`
+}
+
+func (m *testMessage) ActionID() vaa.Action {
+	return actionTestMessage
+}
+
+func (m *testMessage) Serialize() ([]byte, error) {
+	return m.Data, nil
+}
+
+func init() {
+	vaa.RegisterPayloadType(vaa.PayloadType{
+		Action: actionTestMessage,
+		Name:   "test_message",
+		Parse: func(r io.Reader) (vaa.Payload, error) {
+			b, err := ioutil.ReadAll(r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read data: %w", err)
+			}
+			return &testMessage{Data: b}, nil
+		},
+		New: func() vaa.Payload { return &testMessage{} },
+	})
+}
+
+func TestCustomPayloadType(t *testing.T) {
+	v := &vaa.VAA{
+		Version:          vaa.SupportedVAAVersion,
+		GuardianSetIndex: 1,
+		Timestamp:        time.Unix(1605105600, 0),
+		Payload:          &testMessage{Data: []byte("hello")},
+	}
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	v.AddSignature(key, 0)
+
+	b, err := v.Marshal()
+	require.NoError(t, err)
+	parsed, err := vaa.Unmarshal(b)
+	require.NoError(t, err)
+	assert.EqualValues(t, v, parsed)
+
+	j, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.Contains(t, string(j), `"type":"test_message"`)
+
+	var fromJSON vaa.VAA
+	require.NoError(t, json.Unmarshal(j, &fromJSON))
+	assert.EqualValues(t, v, &fromJSON)
+}
+
+func TestRegisterPayloadTypeRejectsDuplicates(t *testing.T) {
+	parse := func(r io.Reader) (vaa.Payload, error) { return nil, nil }
+	newPayload := func() vaa.Payload { return &testMessage{} }
+
+	assert.Panics(t, func() {
+		vaa.RegisterPayloadType(vaa.PayloadType{Action: vaa.ActionTransfer, Name: "other", Parse: parse, New: newPayload})
+	})
+	assert.Panics(t, func() {
+		vaa.RegisterPayloadType(vaa.PayloadType{Action: 0x7e, Name: "transfer", Parse: parse, New: newPayload})
+	})
+	assert.Panics(t, func() {
+		vaa.RegisterPayloadType(vaa.PayloadType{Action: 0x7e, Name: "incomplete"})
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/ecdsa"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -27,7 +28,7 @@ type (
 		// Timestamp when the VAA was created
 		Timestamp time.Time
 		// Payload of the VAA. This describes the action to be performed
-		Payload vaaBody
+		Payload Payload
 	}
 
 	// ChainID of a Wormhole chain
@@ -44,7 +45,7 @@ type (
 		// Index of the validator
 		Index uint8
 		// Signature data
-		Signature [65]byte // TODO: hex marshaller
+		Signature [65]byte
 	}
 
 	// AssetMeta describes an asset within the Wormhole protocol
@@ -57,9 +58,12 @@ type (
 		Decimals uint8
 	}
 
-	vaaBody interface {
-		getActionID() Action
-		serialize() ([]byte, error)
+	// Payload is the action-specific body of a VAA. Payload types are registered using RegisterPayloadType.
+	Payload interface {
+		// ActionID returns the action identifying the payload type.
+		ActionID() Action
+		// Serialize returns the binary representation of the payload, excluding the action.
+		Serialize() ([]byte, error)
 	}
 
 	BodyTransfer struct {
@@ -79,6 +83,17 @@ type (
 		Amount *big.Int
 	}
 
+	// BodyTransferWithPayload is a transfer which carries a payload for the recipient, allowing contracts on
+	// the target chain to act on the transfer (like swapping the tokens on arrival).
+	BodyTransferWithPayload struct {
+		BodyTransfer
+		// Fee is the part of Amount paid to the relayer that submits the VAA on the target chain. Zero if the
+		// sender doesn't pay a fee.
+		Fee *big.Int
+		// Payload is sender-provided data for the recipient, of at most MaxTransferPayloadLength bytes
+		Payload []byte
+	}
+
 	BodyGuardianSetUpdate struct {
 		// Key is the new guardian set key
 		Keys []common.Address
@@ -86,6 +101,41 @@ type (
 		NewIndex uint32
 	}
 
+	// BodyMessage is an arbitrary message published by an emitter on a chain, for cross-chain message passing.
+	BodyMessage struct {
+		// EmitterChain is the id of the chain the message was published on
+		EmitterChain ChainID
+		// EmitterAddress is the address of the contract/program/account that published the message
+		EmitterAddress Address
+		// Sequence is the emitter's sequence number of the message
+		Sequence uint64
+		// ConsistencyLevel is the level of finality the emitter requested before the message was observed
+		ConsistencyLevel uint8
+		// Payload is the message data, of at most MaxMessagePayloadLength bytes
+		Payload []byte
+	}
+
+	// BodyBatch commits to a batch of transfers observed in the same source chain block, such that a single set
+	// of signatures covers all of them. Transfers are redeemed using an inclusion proof (see BatchProof).
+	BodyBatch struct {
+		// SourceChain is the chain all transfers in the batch originate from
+		SourceChain ChainID
+		// BlockHash identifies the source chain block the transfers were observed in
+		BlockHash common.Hash
+		// Count is the number of transfers in the batch, at most MaxBatchSize
+		Count uint16
+		// Root is the Merkle root of the batch's transfers (see BatchLeaf)
+		Root common.Hash
+	}
+
+	// BodyBridgePause stops or resumes transfers to and from a chain.
+	BodyBridgePause struct {
+		// ChainID is the chain to pause or unpause
+		ChainID ChainID
+		// Paused is whether transfers to and from the chain are paused
+		Paused bool
+	}
+
 	BodyContractUpgrade struct {
 		// ChainID is the chain on which the contract should be upgraded
 		ChainID uint8
@@ -98,25 +148,47 @@ func (a Address) String() string {
 	return hex.EncodeToString(a[:])
 }
 
+// chainNames maps chain IDs to human-readable names.
+var chainNames = map[ChainID]string{
+	ChainIDSolana:   "solana",
+	ChainIDEthereum: "ethereum",
+	ChainIDTerra:    "terra",
+	ChainIDQtum:     "qtum",
+}
+
+// IsKnown returns whether the chain ID belongs to a chain supported by Wormhole.
+func (c ChainID) IsKnown() bool {
+	_, ok := chainNames[c]
+	return ok
+}
+
 func (c ChainID) String() string {
-	switch c {
-	case ChainIDSolana:
-		return "solana"
-	case ChainIDEthereum:
-		return "ethereum"
-	case ChainIDTerra:
-		return "terra"
-	case ChainIDQtum:
-		return "qtum"
-	default:
-		return fmt.Sprintf("unknown chain ID: %d", c)
+	if name, ok := chainNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown chain ID: %d", c)
+}
+
+// ChainIDFromString returns the chain ID with the given human-readable name.
+func ChainIDFromString(name string) (ChainID, error) {
+	for c, n := range chainNames {
+		if n == name {
+			return c, nil
+		}
 	}
+
+	return 0, fmt.Errorf("unknown chain name: %s", name)
 }
 
 const (
-	ActionGuardianSetUpdate Action = 0x01
-	ActionContractUpgrade   Action = 0x02
-	ActionTransfer          Action = 0x10
+	ActionGuardianSetUpdate   Action = 0x01
+	ActionContractUpgrade     Action = 0x02
+	ActionBridgePause         Action = 0x03
+	ActionTransfer            Action = 0x10
+	ActionTransferWithPayload Action = 0x11
+	ActionMessage             Action = 0x20
+	ActionBatch               Action = 0x30
 
 	// ChainIDSolana is the ChainID of Solana
 	ChainIDSolana = 1
@@ -129,83 +201,106 @@ const (
 
 	minVAALength        = 1 + 4 + 52 + 4 + 1 + 1
 	SupportedVAAVersion = 0x01
+
+	// MaxGuardianCount is the maximum size of a guardian set, and therefore the maximum number of signatures on
+	// a VAA and keys in a guardian set update. See common.MaxGuardianCount.
+	MaxGuardianCount = 19
+
+	// MaxMessagePayloadLength is the maximum length of a BodyMessage payload, which bounds the size of VAAs.
+	MaxMessagePayloadLength = 1000
+	// MaxTransferPayloadLength is the maximum length of a BodyTransferWithPayload payload.
+	MaxTransferPayloadLength = MaxMessagePayloadLength
+	// MaxBatchSize is the maximum number of transfers in a BodyBatch.
+	MaxBatchSize = 256
 )
 
-// Unmarshal deserializes the binary representation of a VAA
+// Unmarshal deserializes the binary representation of a VAA. Only canonical encodings are accepted - any VAA
+// returned by Unmarshal serializes to exactly the given data. Errors are of type *ParseError.
 func Unmarshal(data []byte) (*VAA, error) {
 	if len(data) < minVAALength {
-		return nil, fmt.Errorf("VAA is too short")
+		return nil, &ParseError{Field: "vaa", Err: ErrTruncated}
 	}
 	v := &VAA{}
 
 	v.Version = data[0]
 	if v.Version != SupportedVAAVersion {
-		return nil, fmt.Errorf("unsupported VAA version: %d", v.Version)
+		return nil, &ParseError{Field: "version", Err: fmt.Errorf("%w: %d", ErrUnsupportedVersion, v.Version)}
 	}
 
 	reader := bytes.NewReader(data[1:])
 
-	if err := binary.Read(reader, binary.BigEndian, &v.GuardianSetIndex); err != nil {
-		return nil, fmt.Errorf("failed to read guardian set index: %w", err)
+	if err := readField(reader, "guardian_set_index", &v.GuardianSetIndex); err != nil {
+		return nil, err
 	}
 
-	lenSignatures, er := reader.ReadByte()
-	if er != nil {
-		return nil, fmt.Errorf("failed to read signature length")
+	var lenSignatures uint8
+	if err := readField(reader, "signatures", &lenSignatures); err != nil {
+		return nil, err
+	}
+	if lenSignatures > MaxGuardianCount {
+		return nil, &ParseError{Field: "signatures", Err: fmt.Errorf("%w: %d", ErrTooManySignatures, lenSignatures)}
 	}
 
 	v.Signatures = make([]*Signature, lenSignatures)
 	for i := 0; i < int(lenSignatures); i++ {
-		index, err := reader.ReadByte()
-		if err != nil {
-			return nil, fmt.Errorf("failed to read validator index [%d]", i)
+		sig := &Signature{}
+		if err := readField(reader, fmt.Sprintf("signatures[%d].index", i), &sig.Index); err != nil {
+			return nil, err
 		}
-
-		signature := [65]byte{}
-		if n, err := reader.Read(signature[:]); err != nil || n != 65 {
-			return nil, fmt.Errorf("failed to read signature [%d]: %w", i, err)
+		// Strictly ascending indices rule out duplicate signatures by the same guardian.
+		if i > 0 && sig.Index <= v.Signatures[i-1].Index {
+			return nil, &ParseError{Field: fmt.Sprintf("signatures[%d].index", i), Err: ErrSignatureOrder}
 		}
-
-		v.Signatures[i] = &Signature{
-			Index:     index,
-			Signature: signature,
+		if err := readField(reader, fmt.Sprintf("signatures[%d].signature", i), &sig.Signature); err != nil {
+			return nil, err
 		}
+		v.Signatures[i] = sig
 	}
 
 	unixSeconds := uint32(0)
-	if err := binary.Read(reader, binary.BigEndian, &unixSeconds); err != nil {
-		return nil, fmt.Errorf("failed to read timestamp: %w", err)
+	if err := readField(reader, "timestamp", &unixSeconds); err != nil {
+		return nil, err
 	}
 	v.Timestamp = time.Unix(int64(unixSeconds), 0)
 
-	var (
-		action uint8
-	)
-	if err := binary.Read(reader, binary.BigEndian, &action); err != nil {
-		return nil, fmt.Errorf("failed to read action: %w", err)
+	var action Action
+	if err := readField(reader, "action", &action); err != nil {
+		return nil, err
 	}
 
-	currentPos := len(data) - reader.Len()
+	t, ok := payloadTypes[action]
+	if !ok {
+		return nil, &ParseError{Field: "action", Err: fmt.Errorf("%w: %d", ErrUnknownAction, action)}
+	}
 
-	payloadReader := bytes.NewReader(data[currentPos:])
 	var err error
-	switch Action(action) {
-	case ActionGuardianSetUpdate:
-		v.Payload, err = parseBodyGuardianSetUpdate(payloadReader)
-	case ActionTransfer:
-		v.Payload, err = parseBodyTransfer(payloadReader)
-	case ActionContractUpgrade:
-		v.Payload, err = parseBodyContractUpgrade(payloadReader)
-	default:
-		return nil, fmt.Errorf("unknown action: %d", action)
-	}
+	v.Payload, err = t.Parse(reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse payload: %w", err)
+		var perr *ParseError
+		if errors.As(err, &perr) {
+			return nil, err
+		}
+		return nil, &ParseError{Field: "payload", Err: err}
+	}
+
+	if reader.Len() != 0 {
+		return nil, &ParseError{Field: "payload", Err: fmt.Errorf("%w: %d bytes", ErrTrailingData, reader.Len())}
 	}
 
 	return v, nil
 }
 
+// readField reads a fixed-size field from r, reporting errors as *ParseError.
+func readField(r io.Reader, field string, data interface{}) error {
+	if err := binary.Read(r, binary.BigEndian, data); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = ErrTruncated
+		}
+		return &ParseError{Field: field, Err: err}
+	}
+	return nil
+}
+
 // signingBody returns the binary representation of the data that is relevant for signing and verifying the VAA
 func (v *VAA) signingBody() ([]byte, error) {
 	return v.serializeBody()
@@ -279,9 +374,9 @@ func (v *VAA) Marshal() ([]byte, error) {
 func (v *VAA) serializeBody() ([]byte, error) {
 	buf := new(bytes.Buffer)
 	MustWrite(buf, binary.BigEndian, uint32(v.Timestamp.Unix()))
-	MustWrite(buf, binary.BigEndian, v.Payload.getActionID())
+	MustWrite(buf, binary.BigEndian, v.Payload.ActionID())
 
-	payloadData, err := v.Payload.serialize()
+	payloadData, err := v.Payload.Serialize()
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize payload: %w", err)
 	}
@@ -309,54 +404,38 @@ func (v *VAA) AddSignature(key *ecdsa.PrivateKey, index uint8) {
 	})
 }
 
-func parseBodyTransfer(r io.Reader) (*BodyTransfer, error) {
-	b := &BodyTransfer{}
-
-	if err := binary.Read(r, binary.BigEndian, &b.Nonce); err != nil {
-		return nil, fmt.Errorf("failed to read nonce: %w", err)
-	}
-
-	if err := binary.Read(r, binary.BigEndian, &b.SourceChain); err != nil {
-		return nil, fmt.Errorf("failed to read source chain: %w", err)
-	}
-
-	if err := binary.Read(r, binary.BigEndian, &b.TargetChain); err != nil {
-		return nil, fmt.Errorf("failed to read target chain: %w", err)
-	}
-
-	if n, err := r.Read(b.SourceAddress[:]); err != nil || n != 32 {
-		return nil, fmt.Errorf("failed to read source address: %w", err)
-	}
-
-	if n, err := r.Read(b.TargetAddress[:]); err != nil || n != 32 {
-		return nil, fmt.Errorf("failed to read target address: %w", err)
-	}
-
-	b.Asset = &AssetMeta{}
-	if err := binary.Read(r, binary.BigEndian, &b.Asset.Chain); err != nil {
-		return nil, fmt.Errorf("failed to read asset chain: %w", err)
-	}
-	if n, err := r.Read(b.Asset.Address[:]); err != nil || n != 32 {
-		return nil, fmt.Errorf("failed to read asset address: %w", err)
-	}
-	if err := binary.Read(r, binary.BigEndian, &b.Asset.Decimals); err != nil {
-		return nil, fmt.Errorf("failed to read asset decimals: %w", err)
-	}
-
-	var amountBytes [32]byte
-	if n, err := r.Read(amountBytes[:]); err != nil || n != 32 {
-		return nil, fmt.Errorf("failed to read amount: %w", err)
+func parseBodyTransfer(r io.Reader) (Payload, error) {
+	b := &BodyTransfer{Asset: &AssetMeta{}}
+
+	var amount [32]byte
+	for _, f := range []struct {
+		name string
+		data interface{}
+	}{
+		{"nonce", &b.Nonce},
+		{"source_chain", &b.SourceChain},
+		{"target_chain", &b.TargetChain},
+		{"source_address", &b.SourceAddress},
+		{"target_address", &b.TargetAddress},
+		{"asset.chain", &b.Asset.Chain},
+		{"asset.address", &b.Asset.Address},
+		{"asset.decimals", &b.Asset.Decimals},
+		{"amount", &amount},
+	} {
+		if err := readField(r, f.name, f.data); err != nil {
+			return nil, err
+		}
 	}
-	b.Amount = new(big.Int).SetBytes(amountBytes[:])
+	b.Amount = new(big.Int).SetBytes(amount[:])
 
 	return b, nil
 }
 
-func (v *BodyTransfer) getActionID() Action {
+func (v *BodyTransfer) ActionID() Action {
 	return ActionTransfer
 }
 
-func (v *BodyTransfer) serialize() ([]byte, error) {
+func (v *BodyTransfer) Serialize() ([]byte, error) {
 	buf := new(bytes.Buffer)
 	MustWrite(buf, binary.BigEndian, v.Nonce)
 	MustWrite(buf, binary.BigEndian, v.SourceChain)
@@ -379,33 +458,103 @@ func (v *BodyTransfer) serialize() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func parseBodyGuardianSetUpdate(r io.Reader) (*BodyGuardianSetUpdate, error) {
+func parseBodyTransferWithPayload(r io.Reader) (Payload, error) {
+	t, err := parseBodyTransfer(r)
+	if err != nil {
+		return nil, err
+	}
+	b := &BodyTransferWithPayload{BodyTransfer: *t.(*BodyTransfer)}
+
+	var fee [32]byte
+	if err := readField(r, "fee", &fee); err != nil {
+		return nil, err
+	}
+	b.Fee = new(big.Int).SetBytes(fee[:])
+	if b.Fee.Cmp(b.Amount) > 0 {
+		return nil, &ParseError{Field: "fee", Err: ErrFeeExceedsAmount}
+	}
+
+	var length uint16
+	if err := readField(r, "payload", &length); err != nil {
+		return nil, err
+	}
+	if length > MaxTransferPayloadLength {
+		return nil, &ParseError{Field: "payload", Err: fmt.Errorf("%w: %d bytes", ErrPayloadTooLong, length)}
+	}
+	b.Payload = make([]byte, length)
+	if err := readField(r, "payload", b.Payload); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (v *BodyTransferWithPayload) ActionID() Action {
+	return ActionTransferWithPayload
+}
+
+func (v *BodyTransferWithPayload) Serialize() ([]byte, error) {
+	if len(v.Payload) > MaxTransferPayloadLength {
+		return nil, fmt.Errorf("payload too long: %d bytes, maximum is %d", len(v.Payload), MaxTransferPayloadLength)
+	}
+
+	transfer, err := v.BodyTransfer.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(transfer)
+	fee := v.Fee
+	if fee == nil {
+		fee = new(big.Int)
+	}
+	buf.Write(common.LeftPadBytes(fee.Bytes(), 32))
+	MustWrite(buf, binary.BigEndian, uint16(len(v.Payload)))
+	buf.Write(v.Payload)
+
+	return buf.Bytes(), nil
+}
+
+func parseBodyGuardianSetUpdate(r io.Reader) (Payload, error) {
 	b := &BodyGuardianSetUpdate{}
 
-	if err := binary.Read(r, binary.BigEndian, &b.NewIndex); err != nil {
-		return nil, fmt.Errorf("failed to read new index: %w", err)
+	if err := readField(r, "new_index", &b.NewIndex); err != nil {
+		return nil, err
 	}
 
 	keyLen := uint8(0)
-	if err := binary.Read(r, binary.BigEndian, &keyLen); err != nil {
-		return nil, fmt.Errorf("failed to read guardianset key len: %w", err)
+	if err := readField(r, "keys", &keyLen); err != nil {
+		return nil, err
+	}
+	if keyLen > MaxGuardianCount {
+		return nil, &ParseError{Field: "keys", Err: fmt.Errorf("%w: %d", ErrTooManyKeys, keyLen)}
 	}
+
+	seen := make(map[common.Address]bool, keyLen)
 	for i := 0; i < int(keyLen); i++ {
+		field := fmt.Sprintf("keys[%d]", i)
 		key := common.Address{}
-		if n, err := r.Read(key[:]); err != nil || n != 20 {
-			return nil, fmt.Errorf("failed to read guardianset key [%d]: %w", i, err)
+		if err := readField(r, field, &key); err != nil {
+			return nil, err
+		}
+		if key == (common.Address{}) {
+			return nil, &ParseError{Field: field, Err: ErrZeroAddress}
 		}
+		if seen[key] {
+			return nil, &ParseError{Field: field, Err: fmt.Errorf("%w: %s", ErrDuplicateKey, key.Hex())}
+		}
+		seen[key] = true
 		b.Keys = append(b.Keys, key)
 	}
 
 	return b, nil
 }
 
-func (v *BodyGuardianSetUpdate) getActionID() Action {
+func (v *BodyGuardianSetUpdate) ActionID() Action {
 	return ActionGuardianSetUpdate
 }
 
-func (v *BodyGuardianSetUpdate) serialize() ([]byte, error) {
+func (v *BodyGuardianSetUpdate) Serialize() ([]byte, error) {
 	buf := new(bytes.Buffer)
 
 	MustWrite(buf, binary.BigEndian, v.NewIndex)
@@ -417,25 +566,146 @@ func (v *BodyGuardianSetUpdate) serialize() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func parseBodyContractUpgrade(r io.Reader) (*BodyContractUpgrade, error) {
-	b := &BodyContractUpgrade{}
+func parseBodyMessage(r io.Reader) (Payload, error) {
+	b := &BodyMessage{}
+
+	var length uint16
+	for _, f := range []struct {
+		name string
+		data interface{}
+	}{
+		{"emitter_chain", &b.EmitterChain},
+		{"emitter_address", &b.EmitterAddress},
+		{"sequence", &b.Sequence},
+		{"consistency_level", &b.ConsistencyLevel},
+		{"payload", &length},
+	} {
+		if err := readField(r, f.name, f.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if length > MaxMessagePayloadLength {
+		return nil, &ParseError{Field: "payload", Err: fmt.Errorf("%w: %d bytes", ErrPayloadTooLong, length)}
+	}
+	b.Payload = make([]byte, length)
+	if err := readField(r, "payload", b.Payload); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (v *BodyMessage) ActionID() Action {
+	return ActionMessage
+}
+
+func (v *BodyMessage) Serialize() ([]byte, error) {
+	if len(v.Payload) > MaxMessagePayloadLength {
+		return nil, fmt.Errorf("payload too long: %d bytes, maximum is %d", len(v.Payload), MaxMessagePayloadLength)
+	}
+
+	buf := new(bytes.Buffer)
+	MustWrite(buf, binary.BigEndian, v.EmitterChain)
+	buf.Write(v.EmitterAddress[:])
+	MustWrite(buf, binary.BigEndian, v.Sequence)
+	MustWrite(buf, binary.BigEndian, v.ConsistencyLevel)
+	MustWrite(buf, binary.BigEndian, uint16(len(v.Payload)))
+	buf.Write(v.Payload)
+
+	return buf.Bytes(), nil
+}
+
+func parseBodyBatch(r io.Reader) (Payload, error) {
+	b := &BodyBatch{}
+
+	for _, f := range []struct {
+		name string
+		data interface{}
+	}{
+		{"source_chain", &b.SourceChain},
+		{"block_hash", &b.BlockHash},
+		{"count", &b.Count},
+		{"root", &b.Root},
+	} {
+		if err := readField(r, f.name, f.data); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := binary.Read(r, binary.BigEndian, &b.ChainID); err != nil {
-		return nil, fmt.Errorf("failed to read chain id: %w", err)
+	if b.Count == 0 || b.Count > MaxBatchSize {
+		return nil, &ParseError{Field: "count", Err: fmt.Errorf("%w: %d", ErrBatchSize, b.Count)}
 	}
 
-	if n, err := r.Read(b.NewContract[:]); err != nil || n != 32 {
-		return nil, fmt.Errorf("failed to read new contract address: %w", err)
+	return b, nil
+}
+
+func (v *BodyBatch) ActionID() Action {
+	return ActionBatch
+}
+
+func (v *BodyBatch) Serialize() ([]byte, error) {
+	if v.Count == 0 || v.Count > MaxBatchSize {
+		return nil, fmt.Errorf("invalid batch size %d, must be between 1 and %d", v.Count, MaxBatchSize)
+	}
+
+	buf := new(bytes.Buffer)
+	MustWrite(buf, binary.BigEndian, v.SourceChain)
+	buf.Write(v.BlockHash[:])
+	MustWrite(buf, binary.BigEndian, v.Count)
+	buf.Write(v.Root[:])
+
+	return buf.Bytes(), nil
+}
+
+func parseBodyBridgePause(r io.Reader) (Payload, error) {
+	b := &BodyBridgePause{}
+
+	var paused uint8
+	if err := readField(r, "chain_id", &b.ChainID); err != nil {
+		return nil, err
+	}
+	if err := readField(r, "paused", &paused); err != nil {
+		return nil, err
+	}
+	if paused > 1 {
+		return nil, &ParseError{Field: "paused", Err: fmt.Errorf("%w: %d", ErrInvalidBool, paused)}
+	}
+	b.Paused = paused == 1
+
+	return b, nil
+}
+
+func (v *BodyBridgePause) ActionID() Action {
+	return ActionBridgePause
+}
+
+func (v *BodyBridgePause) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	MustWrite(buf, binary.BigEndian, v.ChainID)
+	MustWrite(buf, binary.BigEndian, v.Paused)
+
+	return buf.Bytes(), nil
+}
+
+func parseBodyContractUpgrade(r io.Reader) (Payload, error) {
+	b := &BodyContractUpgrade{}
+
+	if err := readField(r, "chain_id", &b.ChainID); err != nil {
+		return nil, err
+	}
+	if err := readField(r, "new_contract", &b.NewContract); err != nil {
+		return nil, err
 	}
 
 	return b, nil
 }
 
-func (v *BodyContractUpgrade) getActionID() Action {
+func (v *BodyContractUpgrade) ActionID() Action {
 	return ActionContractUpgrade
 }
 
-func (v *BodyContractUpgrade) serialize() ([]byte, error) {
+func (v *BodyContractUpgrade) Serialize() ([]byte, error) {
 	buf := new(bytes.Buffer)
 
 	MustWrite(buf, binary.BigEndian, v.ChainID)
@@ -3,9 +3,12 @@ package vaa
 import (
 	"crypto/ecdsa"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"math/big"
 	"testing"
@@ -43,6 +46,36 @@ func TestSerializeDeserialize(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "BodyTransferWithPayload",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Signatures: []*Signature{
+					{
+						Index:     1,
+						Signature: [65]byte{},
+					},
+				},
+				Timestamp: time.Unix(2837, 0),
+				Payload: &BodyTransferWithPayload{
+					BodyTransfer: BodyTransfer{
+						Nonce:         38,
+						SourceChain:   2,
+						TargetChain:   1,
+						SourceAddress: Address{2, 1, 4},
+						TargetAddress: Address{2, 1, 3},
+						Asset: &AssetMeta{
+							Chain:   9,
+							Address: Address{9, 2, 4},
+						},
+						Amount: big.NewInt(29),
+					},
+					Fee:     big.NewInt(3),
+					Payload: []byte("swap"),
+				},
+			},
+		},
 		{
 			name: "GuardianSetUpdate",
 			vaa: &VAA{
@@ -56,11 +89,70 @@ func TestSerializeDeserialize(t *testing.T) {
 				},
 				Timestamp: time.Unix(2837, 0),
 				Payload: &BodyGuardianSetUpdate{
-					Keys:     []common.Address{{}, {}},
+					Keys:     []common.Address{{1}, {2}},
 					NewIndex: 2,
 				},
 			},
 		},
+		{
+			name: "Message",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Signatures: []*Signature{
+					{
+						Index:     1,
+						Signature: [65]byte{},
+					},
+				},
+				Timestamp: time.Unix(2837, 0),
+				Payload: &BodyMessage{
+					EmitterChain:     ChainIDSolana,
+					EmitterAddress:   Address{4, 2},
+					Sequence:         1 << 40,
+					ConsistencyLevel: 32,
+					Payload:          []byte("hello"),
+				},
+			},
+		},
+		{
+			name: "Batch",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Signatures: []*Signature{
+					{
+						Index:     1,
+						Signature: [65]byte{},
+					},
+				},
+				Timestamp: time.Unix(2837, 0),
+				Payload: &BodyBatch{
+					SourceChain: ChainIDEthereum,
+					BlockHash:   common.Hash{4, 2},
+					Count:       3,
+					Root:        common.Hash{1, 2, 3},
+				},
+			},
+		},
+		{
+			name: "BridgePause",
+			vaa: &VAA{
+				Version:          1,
+				GuardianSetIndex: 9,
+				Signatures: []*Signature{
+					{
+						Index:     1,
+						Signature: [65]byte{},
+					},
+				},
+				Timestamp: time.Unix(2837, 0),
+				Payload: &BodyBridgePause{
+					ChainID: ChainIDTerra,
+					Paused:  true,
+				},
+			},
+		},
 		{
 			name: "ContractUpgrade",
 			vaa: &VAA{
@@ -132,3 +224,86 @@ func TestVerifySignature(t *testing.T) {
 		addr,
 	}))
 }
+
+func TestUnmarshalStrict(t *testing.T) {
+	transfer := &BodyTransfer{
+		Nonce:         1,
+		SourceChain:   ChainIDEthereum,
+		TargetChain:   ChainIDSolana,
+		TargetAddress: Address{2, 1, 3},
+		Asset:         &AssetMeta{Chain: ChainIDEthereum, Address: Address{9, 2, 4}},
+		Amount:        big.NewInt(29),
+	}
+	marshal := func(sigs []uint8, payload Payload) []byte {
+		v := &VAA{Version: 1, GuardianSetIndex: 9, Timestamp: time.Unix(2837, 0), Payload: payload}
+		for _, i := range sigs {
+			v.Signatures = append(v.Signatures, &Signature{Index: i})
+		}
+		b, err := v.Marshal()
+		require.NoError(t, err)
+		return b
+	}
+	keys := func(n int) []common.Address {
+		k := make([]common.Address, n)
+		for i := range k {
+			k[i] = common.Address{byte(i + 1)}
+		}
+		return k
+	}
+
+	valid := marshal([]uint8{0, 2}, transfer)
+	_, err := Unmarshal(valid)
+	require.NoError(t, err)
+
+	// Message payloads are length-prefixed.
+	longMessage := marshal([]uint8{0}, &BodyMessage{})
+	binary.BigEndian.PutUint16(longMessage[len(longMessage)-2:], MaxMessagePayloadLength+1)
+	shortMessage := marshal([]uint8{0}, &BodyMessage{Payload: []byte{1, 2}})
+	shortMessage = shortMessage[:len(shortMessage)-1]
+
+	highFee := marshal([]uint8{0}, &BodyTransferWithPayload{BodyTransfer: *transfer, Fee: big.NewInt(30)})
+
+	// Batches can't be created with an invalid size, so we patch the count which precedes the root.
+	emptyBatch := marshal([]uint8{0}, &BodyBatch{Count: 1})
+	emptyBatch[len(emptyBatch)-33] = 0
+
+	invalidPause := marshal([]uint8{0}, &BodyBridgePause{ChainID: ChainIDTerra, Paused: true})
+	invalidPause[len(invalidPause)-1] = 2
+
+	// The action follows version, guardian set index, signatures and timestamp.
+	unknownAction := marshal([]uint8{0}, &BodyContractUpgrade{ChainID: 1})
+	unknownAction[1+4+1+66+4] = 0x7d
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{name: "trailing data", data: append(append([]byte{}, valid...), 0), want: ErrTrailingData},
+		{name: "truncated", data: valid[:len(valid)-1], want: ErrTruncated},
+		{name: "unsupported version", data: append([]byte{2}, valid[1:]...), want: ErrUnsupportedVersion},
+		{name: "duplicate signature index", data: marshal([]uint8{1, 1}, transfer), want: ErrSignatureOrder},
+		{name: "descending signature index", data: marshal([]uint8{2, 0}, transfer), want: ErrSignatureOrder},
+		{name: "too many signatures", data: marshal(make([]uint8, MaxGuardianCount+1), transfer), want: ErrTooManySignatures},
+		{name: "unknown action", data: unknownAction, want: ErrUnknownAction},
+		{name: "zero address key", data: marshal([]uint8{0}, &BodyGuardianSetUpdate{Keys: []common.Address{{1}, {}}}), want: ErrZeroAddress},
+		{name: "duplicate key", data: marshal([]uint8{0}, &BodyGuardianSetUpdate{Keys: []common.Address{{1}, {1}}}), want: ErrDuplicateKey},
+		{name: "payload too long", data: longMessage, want: ErrPayloadTooLong},
+		{name: "truncated payload", data: shortMessage, want: ErrTruncated},
+		{name: "fee exceeds amount", data: highFee, want: ErrFeeExceedsAmount},
+		{name: "empty batch", data: emptyBatch, want: ErrBatchSize},
+		{name: "invalid paused flag", data: invalidPause, want: ErrInvalidBool},
+		{name: "too many keys", data: marshal([]uint8{0}, &BodyGuardianSetUpdate{Keys: keys(MaxGuardianCount + 1)}), want: ErrTooManyKeys},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Unmarshal(test.data)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, test.want), "got %v, want %v", err, test.want)
+
+			var perr *ParseError
+			assert.True(t, errors.As(err, &perr))
+		})
+	}
+}